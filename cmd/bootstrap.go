@@ -28,7 +28,8 @@ var bootstrapCmd = &cobra.Command{
 	Use:    fmt.Sprintf("bootstrap [--from=%s] [--to=%s] [--overwrite=%t]", operation.From, operation.To, operation.Overwrite),
 	Hidden: true,
 	Example: `k8tz bootstrap --from=/zoneinfo
-k8tz bootstrap -t/path/to/target`,
+k8tz bootstrap -t/path/to/target
+k8tz bootstrap --timezone-files`,
 	Short: "Bootstraps zoneinfo directory from a source directory",
 	Long: `Bootstraps zoneinfo directory with TZif files from a source directory.
 
@@ -46,5 +47,6 @@ func init() {
 	bootstrapCmd.Flags().StringVarP(&operation.From, "from", "f", operation.From, "Path to directory where to take the files from")
 	bootstrapCmd.Flags().StringVarP(&operation.To, "to", "t", operation.To, "Path to directory where copy the files to")
 	bootstrapCmd.Flags().BoolVarP(&operation.Overwrite, "overwrite", "o", operation.Overwrite, "If true and file already exists in target directory, it will be overwritten. If false it will be skipped.")
+	bootstrapCmd.Flags().BoolVar(&operation.TimezoneFiles, "timezone-files", operation.TimezoneFiles, "Write a file containing the zone name for every zone, to be mounted as /etc/timezone")
 	bootstrapCmd.Flags().BoolVarP(&operation.Verbose, "verbose", "v", operation.Verbose, "Print more verbose logs for debugging")
 }
@@ -43,6 +43,9 @@ kubectl get deploy | k8tz inject --timezone=Europe/Amsterdam - | kubectl apply -
 # Create pod with UTC timezone with hostPath strategy from a yaml file
 k8tz i -tUTC --strategy=hostPath examples/test-pod.yaml | kubectl create -f -
 
+# Only set the TZ environment variable for images that already ship tzdata
+k8tz i -tEurope/Berlin --strategy=env examples/test-pod.yaml | kubectl create -f -
+
 # Create pod with New York timezone from URL with custom private registry
 k8tz inject --image=registry.example.com/myrepo/k8tz:` + version.Version() + ` -tAmerica/New_York https://github.com/k8tz/k8tz/.../examples/test-pod.yaml | kubectl apply -f -
 
@@ -72,10 +75,28 @@ func init() {
 
 	injectCmd.Flags().StringVarP(&patchGenerator.Timezone, "timezone", "t", patchGenerator.Timezone, "Default timezone if not specified explicitly")
 	injectCmd.Flags().StringVar(&patchGenerator.InitContainerName, "name", patchGenerator.InitContainerName, "initContainer name")
+	injectCmd.Flags().StringVar(&patchGenerator.VolumeName, "volume-name", patchGenerator.VolumeName, "Name of the injected volume, a numeric suffix is added when the pod already has a volume with that name")
 	injectCmd.Flags().StringVarP(&patchGenerator.InitContainerImage, "image", "i", patchGenerator.InitContainerImage, "initContainer bootstrap image")
+	injectCmd.Flags().StringVar(&patchGenerator.TzdataImage, "tzdata-image", patchGenerator.TzdataImage, "Image mounted by the imageVolume strategy, e.g. the data-only quay.io/k8tz/tzdata image (defaults to --image)")
+	injectCmd.Flags().StringVar((*string)(&patchGenerator.ImagePullPolicy), "image-pull-policy", string(patchGenerator.ImagePullPolicy), "Pull policy of the injected bootstrap and tzdata images (Always/IfNotPresent/Never)")
+	injectCmd.Flags().StringSliceVar(&patchGenerator.ImagePullSecrets, "image-pull-secrets", patchGenerator.ImagePullSecrets, "Names of image pull secrets added to pods for pulling the injected images")
 	injectCmd.Flags().StringVar(&patchGenerator.InitContainerResources, "resources", patchGenerator.InitContainerResources, "initContainer compute resources in JSON format")
-	injectCmd.Flags().StringVarP((*string)(&patchGenerator.Strategy), "strategy", "s", string(patchGenerator.Strategy), "Default injection strategy if not specified explicitly (hostPath/initContainer)")
+	injectCmd.Flags().StringVarP((*string)(&patchGenerator.Strategy), "strategy", "s", string(patchGenerator.Strategy), "Default injection strategy if not specified explicitly (hostPath/initContainer/imageVolume/configMap/env)")
 	injectCmd.Flags().StringVar(&patchGenerator.HostPathPrefix, "hostpath", patchGenerator.HostPathPrefix, "Location of TZif files on host machines")
 	injectCmd.Flags().StringVarP(&patchGenerator.LocalTimePath, "mountpath", "m", patchGenerator.LocalTimePath, "Mount path for TZif file on containers")
+	injectCmd.Flags().StringVar(&patchGenerator.ZoneinfoMountPath, "zoneinfo-path", patchGenerator.ZoneinfoMountPath, "Mount path for the zoneinfo directory on containers, or 'none' to mount only the TZif file without shadowing the image's zoneinfo")
+	injectCmd.Flags().StringVar(&patchGenerator.TimezoneFilePath, "timezone-file-path", patchGenerator.TimezoneFilePath, "Mount path for a file containing the timezone name (e.g. /etc/timezone), empty to disable")
+	injectCmd.Flags().StringVar((*string)(&patchGenerator.MountConflictPolicy), "mount-conflict-policy", string(patchGenerator.MountConflictPolicy), "What to do with containers that already mount a path used by k8tz (replace/skip/reject)")
+	injectCmd.Flags().StringSliceVar(&patchGenerator.EnvTimezones, "env-timezones", patchGenerator.EnvTimezones, "Timezones (or patterns, e.g. Europe/*) available in the images, when set other timezones are rejected with the env injection strategy")
+	injectCmd.Flags().StringVar((*string)(&patchGenerator.RuntimeProfile), "runtime-profile", string(patchGenerator.RuntimeProfile), "Runtime specific environment variables to add besides TZ (none/auto/java/go/dotnet/python)")
+	injectCmd.Flags().BoolVar(&patchGenerator.ImageVolumeLocalTime, "image-volume-localtime", patchGenerator.ImageVolumeLocalTime, "Mount the TZif file from the image volume with imageVolume strategy. Requires kubernetes >="+inject.ImageVolumeLocalTimeMinVersion)
+	injectCmd.Flags().StringVar((*string)(&patchGenerator.PodSecurityLevel), "pod-security-level", string(patchGenerator.PodSecurityLevel), "Pod Security Standards level the injected content must comply with (privileged/baseline/restricted)")
+	injectCmd.Flags().StringVar(&patchGenerator.EmptyDirSizeLimit, "empty-dir-size-limit", patchGenerator.EmptyDirSizeLimit, "Size limit of the emptyDir volume of the initContainer injection strategy, e.g. 4Mi")
+	injectCmd.Flags().StringVar((*string)(&patchGenerator.EmptyDirMedium), "empty-dir-medium", string(patchGenerator.EmptyDirMedium), "Medium of the emptyDir volume of the initContainer injection strategy (empty for node default or Memory)")
+	injectCmd.Flags().StringVar((*string)(&patchGenerator.WindowsPolicy), "windows-policy", string(patchGenerator.WindowsPolicy), "How Windows pods are injected (skip/env/mapping)")
+	injectCmd.Flags().StringToStringVar(&patchGenerator.WindowsTimezones, "windows-timezones", patchGenerator.WindowsTimezones, "Windows time zone IDs of timezones for the mapping Windows policy, e.g. Europe/London=GMT Standard Time")
 	injectCmd.Flags().BoolVar(&patchGenerator.CronJobTimeZone, "cronJobTimeZone", patchGenerator.CronJobTimeZone, "Enable CronJob injection. Requires kubernetes >=1.24.0-beta.0 and the 'CronJobTimeZone' feature gate enabled (alpha)")
+	injectCmd.Flags().BoolVar(&patchGenerator.CronJobScheduleRewrite, "cronjob-schedule-rewrite", patchGenerator.CronJobScheduleRewrite, "Rewrite the schedule of CronJobs to UTC for the current DST period, for clusters without the timeZone of CronJobs")
+	injectCmd.MarkFlagsMutuallyExclusive("cronJobTimeZone", "cronjob-schedule-rewrite")
+	injectCmd.Flags().BoolVar(&patchGenerator.CronJobPodTemplate, "cronjob-pod-template", patchGenerator.CronJobPodTemplate, "Inject the pod template of CronJobs' jobs, independently of --cronJobTimeZone")
 }
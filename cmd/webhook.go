@@ -69,14 +69,43 @@ func init() {
 	webhookCmd.Flags().StringVar(&webhook.Address, "addr", webhook.Address, "Webhook bind address")
 	webhookCmd.Flags().StringVarP(&webhook.Handler.DefaultTimezone, "timezone", "t", webhook.Handler.DefaultTimezone, "Default timezone if not specified explicitly")
 	webhookCmd.Flags().StringVar(&webhook.Handler.ContainerName, "container-name", webhook.Handler.ContainerName, "initContainer name")
+	webhookCmd.Flags().StringVar(&webhook.Handler.VolumeName, "volume-name", webhook.Handler.VolumeName, "Name of the injected volume, a numeric suffix is added when the pod already has a volume with that name")
 	webhookCmd.Flags().StringVar(&webhook.Handler.BootstrapImage, "bootstrap-image", webhook.Handler.BootstrapImage, "initContainer bootstrap image")
+	webhookCmd.Flags().StringVar(&webhook.Handler.TzdataImage, "tzdata-image", webhook.Handler.TzdataImage, "Image mounted by the imageVolume strategy, e.g. the data-only quay.io/k8tz/tzdata image (defaults to the bootstrap image)")
+	webhookCmd.Flags().StringVar((*string)(&webhook.Handler.ImagePullPolicy), "image-pull-policy", string(webhook.Handler.ImagePullPolicy), "Pull policy of the injected bootstrap and tzdata images (Always/IfNotPresent/Never)")
+	webhookCmd.Flags().StringSliceVar(&webhook.Handler.ImagePullSecrets, "image-pull-secrets", webhook.Handler.ImagePullSecrets, "Names of image pull secrets added to pods for pulling the injected images")
 	webhookCmd.Flags().BoolVar(&webhook.Handler.BootstrapVerbose, "bootstrap-verbose", webhook.Handler.BootstrapVerbose, "Print more verbose logs inside the bootstrap initContainer for debugging")
 	webhookCmd.Flags().StringVar(&webhook.Handler.BootstrapContainerResources, "bootstrap-resources", webhook.Handler.BootstrapContainerResources, "initContainer compute resources in JSON format")
 	webhookCmd.Flags().StringVar(&webhook.Handler.HostPathPrefix, "hostPathPrefix", webhook.Handler.HostPathPrefix, "Location of zoneinfo on host machines")
 	webhookCmd.Flags().StringVar(&webhook.Handler.LocalTimePath, "localTimePath", webhook.Handler.LocalTimePath, "Mount path for TZif file on containers")
-	webhookCmd.Flags().StringVarP((*string)(&webhook.Handler.DefaultInjectionStrategy), "injection-strategy", "s", string(webhook.Handler.DefaultInjectionStrategy), "Default injection strategy if not specified explicitly (hostPath/initContainer)")
+	webhookCmd.Flags().StringVar(&webhook.Handler.ZoneinfoMountPath, "zoneinfo-path", webhook.Handler.ZoneinfoMountPath, "Mount path for the zoneinfo directory on containers, or 'none' to mount only the TZif file without shadowing the image's zoneinfo")
+	webhookCmd.Flags().StringVar(&webhook.Handler.TimezoneFilePath, "timezone-file-path", webhook.Handler.TimezoneFilePath, "Mount path for a file containing the timezone name (e.g. /etc/timezone), empty to disable")
+	webhookCmd.Flags().StringVarP((*string)(&webhook.Handler.DefaultInjectionStrategy), "injection-strategy", "s", string(webhook.Handler.DefaultInjectionStrategy), "Default injection strategy if not specified explicitly (hostPath/initContainer/imageVolume/configMap/env/auto)")
+	webhookCmd.Flags().StringSliceVar(&webhook.Handler.AutoStrategies, "auto-strategies", webhook.Handler.AutoStrategies, "Ordered injection strategies tried by the auto strategy, the first one supported by the cluster and the namespace is used")
+	webhookCmd.Flags().StringVar(&webhook.Handler.ImageVolumeLocalTimeVersion, "image-volume-localtime-version", webhook.Handler.ImageVolumeLocalTimeVersion, "Minimal kubernetes version that supports file subPath of image volumes, used to decide whether imageVolume strategy mounts localtime")
 	webhookCmd.Flags().BoolVar(&webhook.Handler.InjectByDefault, "inject", webhook.Handler.InjectByDefault, "Whether injection is enabled by default or should be requested by annotation")
+	webhookCmd.Flags().StringVar(&webhook.Handler.DefaultsConfigMap, "defaults-configmap", webhook.Handler.DefaultsConfigMap, "ConfigMap (<namespace>/<name>) whose timezone, injectionStrategy and inject keys are watched and replace --timezone, --injection-strategy and --inject at runtime")
 	webhookCmd.Flags().BoolVar(&webhook.Handler.CronJobTimeZone, "cronJobTimeZone", webhook.Handler.CronJobTimeZone, "Enable CronJob injection. Requires kubernetes >=1.24.0-beta.0 and the 'CronJobTimeZone' feature gate enabled (alpha)")
+	webhookCmd.Flags().BoolVar(&webhook.Handler.CronJobScheduleRewrite, "cronjob-schedule-rewrite", webhook.Handler.CronJobScheduleRewrite, "Rewrite the schedule of CronJobs to UTC, and again at each DST transition, for clusters without the timeZone of CronJobs")
+	webhookCmd.MarkFlagsMutuallyExclusive("cronJobTimeZone", "cronjob-schedule-rewrite")
+	webhookCmd.Flags().StringVar(&webhook.Handler.LeaderElectionNamespace, "leader-election-namespace", webhook.Handler.LeaderElectionNamespace, "Namespace of the Lease that elects the replica rewriting CronJob schedules")
+	webhookCmd.Flags().BoolVar(&webhook.Handler.CronJobPodTemplate, "cronjob-pod-template", webhook.Handler.CronJobPodTemplate, "Inject the pod template of CronJobs' jobs, independently of --cronJobTimeZone")
+	webhookCmd.Flags().StringVar((*string)(&webhook.Handler.MountConflictPolicy), "mount-conflict-policy", string(webhook.Handler.MountConflictPolicy), "What to do with containers that already mount a path used by k8tz (replace/skip/reject)")
+	webhookCmd.Flags().StringSliceVar(&webhook.Handler.EnvTimezones, "env-timezones", webhook.Handler.EnvTimezones, "Timezones (or patterns, e.g. Europe/*) available in the images, when set other timezones are rejected with the env injection strategy")
+	webhookCmd.Flags().StringVar((*string)(&webhook.Handler.RuntimeProfile), "runtime-profile", string(webhook.Handler.RuntimeProfile), "Default runtime specific environment variables to add besides TZ (none/auto/java/go/dotnet/python)")
+	webhookCmd.Flags().BoolVar(&webhook.Handler.ConfigMapController, "configmap-controller", webhook.Handler.ConfigMapController, "Maintain per-timezone ConfigMaps in the namespaces of pods that use the configMap injection strategy")
+	webhookCmd.Flags().StringVar(&webhook.Handler.EmptyDirSizeLimit, "empty-dir-size-limit", webhook.Handler.EmptyDirSizeLimit, "Size limit of the emptyDir volume of the initContainer injection strategy, e.g. 4Mi")
+	webhookCmd.Flags().StringVar((*string)(&webhook.Handler.EmptyDirMedium), "empty-dir-medium", string(webhook.Handler.EmptyDirMedium), "Medium of the emptyDir volume of the initContainer injection strategy (empty for node default or Memory)")
+	webhookCmd.Flags().StringSliceVar(&webhook.Handler.AllowedOverrides, "allowed-overrides", webhook.Handler.AllowedOverrides, "Override annotations (without the k8tz.io/ prefix, e.g. bootstrap-image) that pods and their owners may use, or '*' for all, or an empty value for none. Namespace overrides are always allowed")
+	webhookCmd.Flags().BoolVar(&webhook.Handler.Reinvocation, "reinvocation", webhook.Handler.Reinvocation, "Inject containers added to already injected pods, for webhook configurations with reinvocationPolicy IfNeeded")
+	webhookCmd.Flags().StringSliceVar(&webhook.Handler.TimezoneRules, "timezone-rules", webhook.Handler.TimezoneRules, "Ordered rules mapping the requesting user to a default timezone, as <kind>:<name>=<timezone> where kind is user, group or serviceaccount (<namespace>/<name>)")
+	webhookCmd.Flags().StringSliceVar(&webhook.Handler.RegionTimezones, "region-timezones", webhook.Handler.RegionTimezones, "Timezones of regions and zones, as <region or zone>=<timezone>, used to derive the auto timezone from the topology.kubernetes.io/region and zone node constraints of pods")
+	webhookCmd.Flags().StringVar((*string)(&webhook.Handler.WindowsPolicy), "windows-policy", string(webhook.Handler.WindowsPolicy), "How Windows pods are injected (skip/env/mapping)")
+	webhookCmd.Flags().StringToStringVar(&webhook.Handler.WindowsTimezones, "windows-timezones", webhook.Handler.WindowsTimezones, "Windows time zone IDs of timezones for the mapping Windows policy, e.g. Europe/London=GMT Standard Time")
 	webhookCmd.Flags().BoolVar(&webhook.Handler.PodOwnerLookup, "podOwnerLookup", webhook.Handler.PodOwnerLookup, "Enable beta pod owner annotation lookup")
+	webhookCmd.Flags().StringVar((*string)(&webhook.Handler.ServiceAccountLookup), "serviceAccountLookup", string(webhook.Handler.ServiceAccountLookup), "Position of the pod's ServiceAccount in the annotation lookup (disabled/beforeOwners/afterOwners)")
+	webhookCmd.Flags().StringVar((*string)(&webhook.Handler.MetadataPrecedence), "metadata-precedence", string(webhook.Handler.MetadataPrecedence), "Whether labels or annotations win when an object has both for the same key (annotations/labels)")
+	webhookCmd.Flags().StringVar((*string)(&webhook.Handler.NamespaceHierarchy), "namespace-hierarchy", string(webhook.Handler.NamespaceHierarchy), "How ancestor namespaces, whose annotations are inherited after the pod's namespace, are found (disabled/hnc/label)")
+	webhookCmd.Flags().StringVar(&webhook.Handler.NamespaceParentLabel, "namespace-parent-label", webhook.Handler.NamespaceParentLabel, "Label of a namespace whose value is the name of its parent namespace, for the label namespace hierarchy")
 	webhookCmd.Flags().BoolVar(&webhook.Verbose, "verbose", webhook.Verbose, "Print more verbose logs for debugging")
 }
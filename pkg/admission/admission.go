@@ -23,14 +23,18 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sync/atomic"
 
 	k8tz "github.com/k8tz/k8tz/pkg"
+	"github.com/k8tz/k8tz/pkg/cronschedule"
 	"github.com/k8tz/k8tz/pkg/inject"
+	"github.com/k8tz/k8tz/pkg/tzconfigmap"
 	"github.com/k8tz/k8tz/pkg/version"
 	admission "k8s.io/api/admission/v1beta1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilversion "k8s.io/apimachinery/pkg/util/version"
 	"k8s.io/client-go/kubernetes"
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -48,8 +52,42 @@ type RequestsHandler struct {
 	HostPathPrefix              string
 	LocalTimePath               string
 	CronJobTimeZone             bool
+	CronJobPodTemplate          bool
+	CronJobScheduleRewrite      bool
+	LeaderElectionNamespace     string
 	PodOwnerLookup              bool
+	ServiceAccountLookup        ServiceAccountLookup
+	MetadataPrecedence          MetadataPrecedence
+	NamespaceHierarchy          NamespaceHierarchy
+	NamespaceParentLabel        string
+	MountConflictPolicy         inject.MountConflictPolicy
+	VolumeName                  string
+	ZoneinfoMountPath           string
+	TimezoneFilePath            string
+	RuntimeProfile              inject.RuntimeProfile
+	ConfigMapController         bool
+	EnvTimezones                []string
+	ImageVolumeLocalTimeVersion string
+	TzdataImage                 string
+	ImagePullPolicy             corev1.PullPolicy
+	ImagePullSecrets            []string
+	AutoStrategies              []string
+	AllowedOverrides            []string
+	EmptyDirSizeLimit           string
+	EmptyDirMedium              corev1.StorageMedium
+	Reinvocation                bool
+	TimezoneRules               []string
+	DefaultsConfigMap           string
+	RegionTimezones             []string
+	WindowsPolicy               inject.WindowsPolicy
+	WindowsTimezones            map[string]string
 	clientset                   kubernetes.Interface
+	configMaps                  *tzconfigmap.Controller
+	cronSchedules               *cronschedule.Controller
+	imageVolumeLocalTime        bool
+	timezoneRules               []timezoneRule
+	regionTimezones             map[string]string
+	defaults                    *atomic.Pointer[handlerDefaults]
 }
 
 func NewRequestsHandler() RequestsHandler {
@@ -64,7 +102,22 @@ func NewRequestsHandler() RequestsHandler {
 		HostPathPrefix:              inject.DefaultHostPathPrefix,
 		LocalTimePath:               inject.DefaultLocalTimePath,
 		CronJobTimeZone:             false,
+		CronJobPodTemplate:          false,
+		CronJobScheduleRewrite:      false,
 		PodOwnerLookup:              false,
+		ServiceAccountLookup:        DisabledServiceAccountLookup,
+		MetadataPrecedence:          AnnotationsMetadataPrecedence,
+		NamespaceHierarchy:          DisabledNamespaceHierarchy,
+		MountConflictPolicy:         inject.DefaultMountConflictPolicy,
+		VolumeName:                  inject.DefaultVolumeName,
+		ZoneinfoMountPath:           inject.DefaultZoneinfoMountPath,
+		RuntimeProfile:              inject.DefaultRuntimeProfile,
+		AllowedOverrides:            append([]string{}, DefaultAllowedOverrides...),
+		LeaderElectionNamespace:     "k8tz",
+		ConfigMapController:         false,
+		ImageVolumeLocalTimeVersion: inject.ImageVolumeLocalTimeMinVersion,
+		AutoStrategies:              DefaultAutoStrategies,
+		WindowsPolicy:               inject.DefaultWindowsPolicy,
 	}
 }
 
@@ -95,9 +148,44 @@ func (h *RequestsHandler) InitializeClientset(kubeconfPath string) error {
 	}
 
 	h.clientset = clientset
+	h.detectImageVolumeLocalTime()
+	if h.ConfigMapController {
+		h.configMaps = tzconfigmap.NewController(clientset)
+	}
+
+	if h.CronJobScheduleRewrite {
+		h.cronSchedules = cronschedule.NewController(clientset)
+	}
+
 	return nil
 }
 
+// detectImageVolumeLocalTime checks whether the kubernetes server supports
+// subPath of image volumes, so the imageVolume strategy can mount the TZif
+// file at LocalTimePath as well
+func (h *RequestsHandler) detectImageVolumeLocalTime() {
+	info, err := h.clientset.Discovery().ServerVersion()
+	if err != nil {
+		k8tz.WarningLogger.Printf("failed to detect kubernetes server version, imageVolume strategy will not mount localtime: %v", err)
+		return
+	}
+
+	serverVersion, err := utilversion.ParseGeneric(info.GitVersion)
+	if err != nil {
+		k8tz.WarningLogger.Printf("failed to parse kubernetes server version %s, imageVolume strategy will not mount localtime: %v", info.GitVersion, err)
+		return
+	}
+
+	minVersion, err := utilversion.ParseGeneric(h.ImageVolumeLocalTimeVersion)
+	if err != nil {
+		k8tz.WarningLogger.Printf("invalid minimal kubernetes version %s for imageVolume localtime: %v", h.ImageVolumeLocalTimeVersion, err)
+		return
+	}
+
+	h.imageVolumeLocalTime = serverVersion.AtLeast(minVersion)
+	k8tz.VerboseLogger.Printf("kubernetes server version %s, imageVolume localtime support: %t", info.GitVersion, h.imageVolumeLocalTime)
+}
+
 func (h *RequestsHandler) handleFunc(w http.ResponseWriter, r *http.Request) {
 	review, header, err := h.readAdmissionReview(r)
 	if err != nil {
@@ -115,7 +203,7 @@ func (h *RequestsHandler) handleFunc(w http.ResponseWriter, r *http.Request) {
 
 	k8tz.VerboseLogger.Printf("incoming review request=%+v", *review.Request)
 
-	patches, err := h.handleAdmissionReview(review)
+	patches, err := h.handleAdmissionReview(review, reviewResponse.Response)
 	if err != nil {
 		k8tz.WarningLogger.Printf("rejecting request: error=%v, review=%+v\n", err, *review)
 		reviewResponse.Response.Allowed = false
@@ -152,20 +240,28 @@ func (h *RequestsHandler) handleFunc(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func (h *RequestsHandler) handleAdmissionReview(review *admission.AdmissionReview) (k8tz.Patches, error) {
+// handleAdmissionReview returns the patches for the reviewed object, warnings
+// and audit annotations are set on the response directly
+func (h *RequestsHandler) handleAdmissionReview(review *admission.AdmissionReview, response *admission.AdmissionResponse) (k8tz.Patches, error) {
 	if review.Request.Operation == admission.Create {
 		var patches k8tz.Patches
 		var err error
 		switch review.Request.Resource {
 		case podResource:
-			patches, err = h.handlePodAdmissionRequest(review.Request)
+			patches, err = h.handlePodAdmissionRequest(review.Request, response)
+		case jobResource:
+			patches, err = h.handleJobAdmissionRequest(review.Request, response)
 		case cronJobResource:
-			patches, err = h.handleCronJobAdmissionRequest(review.Request)
+			patches, err = h.handleCronJobAdmissionRequest(review.Request, response)
 		}
 
 		return patches, err
 	}
 
+	if review.Request.Operation == admission.Update && review.Request.Resource == podResource && review.Request.SubResource == ephemeralContainersSubResource {
+		return h.handleEphemeralContainersAdmissionRequest(review.Request)
+	}
+
 	return nil, nil
 
 }
@@ -194,117 +290,292 @@ func (h *RequestsHandler) readAdmissionReview(r *http.Request) (*admission.Admis
 	return review, http.StatusOK, nil
 }
 
-func (h *RequestsHandler) lookupPod(namespace string, pod *corev1.Pod) (*inject.PatchGenerator, error) {
+func (h *RequestsHandler) lookupPod(namespace string, pod *corev1.Pod, rule *timezoneRule) (*inject.PatchGenerator, []annotationSource, error) {
 	namespaceObj, err := h.clientset.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
 	if err != nil {
-		return nil, fmt.Errorf("failed to lookup pod's namespace (%s): %v", formatObjectDetails(pod.ObjectMeta), err)
+		return nil, nil, fmt.Errorf("failed to lookup pod's namespace (%s): %v", formatObjectDetails(pod.ObjectMeta), err)
 	}
 
+	reinvocation := false
 	if _, ok := pod.Annotations[k8tz.InjectedAnnotation]; ok {
-		k8tz.InfoLogger.Printf("skipping pod (%s) because its already injected", formatObjectDetails(pod.ObjectMeta))
-		return nil, nil
+		if !h.Reinvocation {
+			k8tz.InfoLogger.Printf("skipping pod (%s) because its already injected", formatObjectDetails(pod.ObjectMeta))
+			return nil, nil, nil
+		}
+
+		reinvocation = true
+		k8tz.InfoLogger.Printf("pod (%s) is already injected, only containers without timezone will be injected", formatObjectDetails(pod.ObjectMeta))
+	}
+
+	return h.lookupPodGenerator(namespace, pod, namespaceObj, reinvocation, rule)
+}
+
+// lookupEphemeralContainers returns the generator that was used for the
+// injection of the pod, or nil when the pod was not injected
+func (h *RequestsHandler) lookupEphemeralContainers(namespace string, pod *corev1.Pod) (*inject.PatchGenerator, []annotationSource, error) {
+	namespaceObj, err := h.clientset.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to lookup pod's namespace (%s): %v", formatObjectDetails(pod.ObjectMeta), err)
 	}
 
+	if _, ok := pod.Annotations[k8tz.InjectedAnnotation]; !ok {
+		k8tz.InfoLogger.Printf("skipping ephemeral containers of pod (%s) because the pod is not injected", formatObjectDetails(pod.ObjectMeta))
+		return nil, nil, nil
+	}
+
+	return h.lookupPodGenerator(namespace, pod, namespaceObj, true, nil)
+}
+
+// lookupPodTimezone resolves the timezone of the pod from its annotation
+// sources and the rule that matched the requesting user, see lookupTimezone
+func lookupPodTimezone(pod *corev1.Pod, annotationSources []annotationSource, rule *timezoneRule, defaultTimezone string) string {
+	val, source, ok := lookupTimezone(annotationSources, rule)
+	if !ok {
+		return defaultTimezone
+	}
+
+	if source == timezoneRuleSource {
+		k8tz.InfoLogger.Printf("timezone rule %s matched the requesting user of pod (%s)", rule, formatObjectDetails(pod.ObjectMeta))
+	} else {
+		k8tz.InfoLogger.Printf("explicit timezone requested on %s annotation for pod (%s): %s", source, formatObjectDetails(pod.ObjectMeta), val)
+	}
+
+	return val
+}
+
+// lookupPodGenerator resolves the generator of the pod from its annotation
+// sources, using the strategy picked on injection for injected pods. The
+// "auto" timezone is derived from the region or zone of the pod, and ignored
+// when it cannot be derived.
+func (h *RequestsHandler) lookupPodGenerator(namespace string, pod *corev1.Pod, namespaceObj *corev1.Namespace, injected bool, rule *timezoneRule) (*inject.PatchGenerator, []annotationSource, error) {
+	var err error
+	defaults := h.currentDefaults()
 	annotationSources := h.lookupPodAnnotationSources(namespace, pod, namespaceObj, h.PodOwnerLookup)
 
 	if val, source, ok := lookupAnnotation(annotationSources, k8tz.InjectAnnotation); ok {
 		if val == "false" {
 			k8tz.InfoLogger.Printf("skipping pod (%s) because annotation on %s is explicitly false for injection", formatObjectDetails(pod.ObjectMeta), source)
-			return nil, nil
+			return nil, nil, nil
 		}
-	} else if !h.InjectByDefault {
+	} else if !defaults.InjectByDefault {
 		k8tz.InfoLogger.Printf("skipping pod (%s) because no other instruction and injection disabled by default", formatObjectDetails(pod.ObjectMeta))
-		return nil, nil
+		return nil, nil, nil
 	}
 
-	timezone := h.DefaultTimezone
-	if val, source, ok := lookupAnnotation(annotationSources, k8tz.TimezoneAnnotation); ok {
-		timezone = val
-		k8tz.InfoLogger.Printf("explicit timezone requested on %s annotation for pod (%s): %s", source, formatObjectDetails(pod.ObjectMeta), val)
+	timezone := lookupPodTimezone(pod, annotationSources, rule, defaults.Timezone)
+	if timezone == k8tz.AutoTimezone {
+		if val, ok := h.topologyTimezone(&pod.Spec); ok {
+			timezone = val
+			k8tz.InfoLogger.Printf("timezone derived from the region or zone of pod (%s): %s", formatObjectDetails(pod.ObjectMeta), val)
+		} else {
+			k8tz.InfoLogger.Printf("could not derive timezone from the region or zone of pod (%s), ignoring %s timezone", formatObjectDetails(pod.ObjectMeta), k8tz.AutoTimezone)
+			timezone = nonAutoTimezone(lookupPodTimezone(pod, withoutAutoTimezone(annotationSources), rule, nonAutoTimezone(defaults.Timezone)))
+		}
 	}
 
-	strategy := h.DefaultInjectionStrategy
+	strategy := defaults.InjectionStrategy
 	if v, source, e := lookupAnnotation(annotationSources, k8tz.InjectionStrategyAnnotation); e {
 		strategy = inject.InjectionStrategy(v)
 		k8tz.InfoLogger.Printf("explicit injection strategy requested on %s annotation for pod (%s): %s", source, formatObjectDetails(pod.ObjectMeta), v)
 	}
 
-	return &inject.PatchGenerator{
+	annotateStrategy := false
+	if v, ok := pod.Annotations[k8tz.InjectedStrategyAnnotation]; ok && injected {
+		// the strategy picked by the auto strategy on the first invocation
+		strategy = inject.InjectionStrategy(v)
+	} else if strategy == inject.AutoInjectionStrategy {
+		strategy, err = h.resolveAutoStrategy(namespaceObj)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		annotateStrategy = true
+		k8tz.InfoLogger.Printf("auto injection strategy resolved to %s for pod (%s)", strategy, formatObjectDetails(pod.ObjectMeta))
+	}
+
+	generator := &inject.PatchGenerator{
 		Strategy:               strategy,
 		Timezone:               timezone,
 		InitContainerName:      h.ContainerName,
 		InitContainerImage:     h.BootstrapImage,
 		InitContainerResources: h.BootstrapContainerResources,
+		InitContainerVerbose:   h.BootstrapVerbose,
 		HostPathPrefix:         h.HostPathPrefix,
 		LocalTimePath:          h.LocalTimePath,
-	}, nil
+		MountConflictPolicy:    h.MountConflictPolicy,
+		VolumeName:             h.VolumeName,
+		ZoneinfoMountPath:      h.ZoneinfoMountPath,
+		TimezoneFilePath:       h.TimezoneFilePath,
+		RuntimeProfile:         h.RuntimeProfile,
+		EnvTimezones:           h.EnvTimezones,
+		ImageVolumeLocalTime:   h.imageVolumeLocalTime,
+		TzdataImage:            h.TzdataImage,
+		ImagePullPolicy:        h.ImagePullPolicy,
+		ImagePullSecrets:       h.ImagePullSecrets,
+		AnnotateStrategy:       annotateStrategy,
+		PodSecurityLevel:       podSecurityLevel(namespaceObj),
+		EmptyDirSizeLimit:      h.EmptyDirSizeLimit,
+		EmptyDirMedium:         h.EmptyDirMedium,
+		Reinvocation:           injected,
+		WindowsPolicy:          h.WindowsPolicy,
+		WindowsTimezones:       h.WindowsTimezones,
+	}
+
+	if err := h.applyOverrides(generator, annotationSources, "pod", pod.ObjectMeta); err != nil {
+		return nil, nil, err
+	}
+
+	return generator, annotationSources, nil
 }
 
-func (h *RequestsHandler) lookupCronJob(namespace string, cronJob *batchv1.CronJob) (*inject.PatchGenerator, error) {
+func (h *RequestsHandler) lookupCronJob(namespace string, cronJob *batchv1.CronJob) (*inject.PatchGenerator, []annotationSource, error) {
+	defaults := h.currentDefaults()
+
 	namespaceObj, err := h.clientset.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
 	if err != nil {
-		return nil, fmt.Errorf("failed to lookup cronJob's namespace (%s): %v", formatObjectDetails(cronJob.ObjectMeta), err)
+		return nil, nil, fmt.Errorf("failed to lookup cronJob's namespace (%s): %v", formatObjectDetails(cronJob.ObjectMeta), err)
 	}
 
-	if _, ok := cronJob.Annotations[k8tz.InjectedAnnotation]; ok {
+	cronJobAnnotations := h.objectAnnotations(&cronJob.ObjectMeta)
+	annotationSources := append([]annotationSource{h.objectAnnotationSource("cronJob", &cronJob.ObjectMeta)}, h.lookupNamespaceAnnotationSources(namespaceObj)...)
+	namespaceAnnotations := mergeAnnotationSources(namespaceAnnotationSources(annotationSources))
+
+	if _, ok := cronJobAnnotations[k8tz.InjectedAnnotation]; ok {
 		k8tz.InfoLogger.Printf("skipping cronJob (%s) because its already injected", formatObjectDetails(cronJob.ObjectMeta))
-		return nil, nil
+		return nil, nil, nil
 	}
 
-	if val, ok := cronJob.Annotations[k8tz.InjectAnnotation]; ok {
+	if val, ok := cronJobAnnotations[k8tz.InjectAnnotation]; ok {
 		if val == "false" {
 			k8tz.InfoLogger.Printf("skipping cronJob (%s) because annotation on cronJob is explicitly false for injection", formatObjectDetails(cronJob.ObjectMeta))
-			return nil, nil
+			return nil, nil, nil
 		}
-	} else if val, ok := namespaceObj.Annotations[k8tz.InjectAnnotation]; ok {
+	} else if val, ok := namespaceAnnotations[k8tz.InjectAnnotation]; ok {
 		if val == "false" {
 			k8tz.InfoLogger.Printf("skipping cronJob (%s) because annotation on namespace is explicitly false for injection", formatObjectDetails(cronJob.ObjectMeta))
-			return nil, nil
+			return nil, nil, nil
 		}
-	} else if !h.InjectByDefault {
+	} else if !defaults.InjectByDefault {
 		k8tz.InfoLogger.Printf("skipping cronJob (%s) because no other instruction and injection disabled by default", formatObjectDetails(cronJob.ObjectMeta))
-		return nil, nil
+		return nil, nil, nil
 	}
 
-	timezone := h.DefaultTimezone
-	if val, ok := cronJob.Annotations[k8tz.TimezoneAnnotation]; ok {
+	timezone := defaults.Timezone
+	if val, ok := cronJobAnnotations[k8tz.TimezoneAnnotation]; ok {
 		timezone = val
 		k8tz.InfoLogger.Printf("explicit timezone requested on cronJob's (%s) annotation: %s", formatObjectDetails(cronJob.ObjectMeta), val)
-	} else if val, ok := namespaceObj.Annotations[k8tz.TimezoneAnnotation]; ok {
+	} else if val, ok := namespaceAnnotations[k8tz.TimezoneAnnotation]; ok {
 		timezone = val
 		k8tz.InfoLogger.Printf("explicit timezone requested on namespace (%s) annotation: %s", formatObjectDetails(cronJob.ObjectMeta), val)
 	}
 
-	return &inject.PatchGenerator{
-		Strategy:               h.DefaultInjectionStrategy,
+	if timezone == k8tz.AutoTimezone {
+		if val, ok := h.topologyTimezone(&cronJob.Spec.JobTemplate.Spec.Template.Spec); ok {
+			timezone = val
+			k8tz.InfoLogger.Printf("timezone derived from the region or zone of cronJob (%s): %s", formatObjectDetails(cronJob.ObjectMeta), val)
+		} else {
+			timezone = nonAutoTimezone(defaults.Timezone)
+			if val, _, ok := lookupAnnotation(withoutAutoTimezone(annotationSources), k8tz.TimezoneAnnotation); ok {
+				timezone = nonAutoTimezone(val)
+			}
+			k8tz.InfoLogger.Printf("could not derive timezone from the region or zone of cronJob (%s), falling back to timezone: %s", formatObjectDetails(cronJob.ObjectMeta), timezone)
+		}
+	}
+
+	strategy := defaults.InjectionStrategy
+	if val, ok := cronJobAnnotations[k8tz.InjectionStrategyAnnotation]; ok {
+		strategy = inject.InjectionStrategy(val)
+		k8tz.InfoLogger.Printf("explicit injection strategy requested on cronJob's (%s) annotation: %s", formatObjectDetails(cronJob.ObjectMeta), val)
+	} else if val, ok := namespaceAnnotations[k8tz.InjectionStrategyAnnotation]; ok {
+		strategy = inject.InjectionStrategy(val)
+		k8tz.InfoLogger.Printf("explicit injection strategy requested on namespace (%s) annotation: %s", formatObjectDetails(cronJob.ObjectMeta), val)
+	}
+
+	annotateStrategy := false
+	if strategy == inject.AutoInjectionStrategy && h.CronJobPodTemplate {
+		// the pod template is injected with the resolved strategy, which is
+		// then annotated for the reinvocation of the job's pods
+		strategy, err = h.resolveAutoStrategy(namespaceObj)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		annotateStrategy = true
+		k8tz.InfoLogger.Printf("auto injection strategy resolved to %s for cronJob (%s)", strategy, formatObjectDetails(cronJob.ObjectMeta))
+	}
+
+	generator := &inject.PatchGenerator{
+		Strategy:               strategy,
 		Timezone:               timezone,
 		InitContainerName:      h.ContainerName,
 		InitContainerImage:     h.BootstrapImage,
 		InitContainerResources: h.BootstrapContainerResources,
+		InitContainerVerbose:   h.BootstrapVerbose,
 		HostPathPrefix:         h.HostPathPrefix,
 		LocalTimePath:          h.LocalTimePath,
 		CronJobTimeZone:        h.CronJobTimeZone,
-	}, nil
+		CronJobPodTemplate:     h.CronJobPodTemplate,
+		CronJobScheduleRewrite: h.CronJobScheduleRewrite,
+		MountConflictPolicy:    h.MountConflictPolicy,
+		VolumeName:             h.VolumeName,
+		ZoneinfoMountPath:      h.ZoneinfoMountPath,
+		TimezoneFilePath:       h.TimezoneFilePath,
+		RuntimeProfile:         h.RuntimeProfile,
+		EnvTimezones:           h.EnvTimezones,
+		ImageVolumeLocalTime:   h.imageVolumeLocalTime,
+		TzdataImage:            h.TzdataImage,
+		ImagePullPolicy:        h.ImagePullPolicy,
+		ImagePullSecrets:       h.ImagePullSecrets,
+		AnnotateStrategy:       annotateStrategy,
+		PodSecurityLevel:       podSecurityLevel(namespaceObj),
+		EmptyDirSizeLimit:      h.EmptyDirSizeLimit,
+		EmptyDirMedium:         h.EmptyDirMedium,
+		WindowsPolicy:          h.WindowsPolicy,
+		WindowsTimezones:       h.WindowsTimezones,
+	}
+
+	if err := h.applyOverrides(generator, annotationSources, "cronJob", cronJob.ObjectMeta); err != nil {
+		return nil, nil, err
+	}
+
+	return generator, annotationSources, nil
 }
 
-func (h *RequestsHandler) handlePodAdmissionRequest(req *admission.AdmissionRequest) (k8tz.Patches, error) {
+func (h *RequestsHandler) handlePodAdmissionRequest(req *admission.AdmissionRequest, response *admission.AdmissionResponse) (k8tz.Patches, error) {
 	raw := req.Object.Raw
 	pod := corev1.Pod{}
 	if _, _, err := k8sdecode.Decode(raw, nil, &pod); err != nil {
 		return nil, fmt.Errorf("could not deserialize pod object: %v", err)
 	}
 
-	generator, err := h.lookupPod(req.Namespace, &pod)
+	rule := h.podTimezoneRule(&pod, req.UserInfo)
+	generator, annotationSources, err := h.lookupPod(req.Namespace, &pod, rule)
 	if err != nil {
 		return nil, fmt.Errorf("failed to lookup generator for pod, error=%w", err)
 	}
 
 	var patches k8tz.Patches
 	if generator != nil {
-		generator.InitContainerVerbose = h.BootstrapVerbose
+		if err := h.enforceAllowedTimezones(req.Namespace, pod.ObjectMeta, annotationSources, generator, response); err != nil {
+			return nil, err
+		}
+
+		if err := h.ensureConfigMap(req.Namespace, req.DryRun, generator); err != nil {
+			return nil, err
+		}
+
+		setTimezoneRuleAuditAnnotation(response, rule)
+		setAnnotationSourcesAuditAnnotation(response, annotationSources, rule)
+
 		k8tz.VerboseLogger.Printf("Generating patches for pod (%s) using generator: %+v", formatObjectDetails(pod.ObjectMeta), *generator)
 		patches, err = generator.Generate(&pod, "")
-		if err != nil {
+		if errors.Is(err, inject.ErrPodSecurityViolation) {
+			// the pod would be rejected by pod security admission anyway, so
+			// it is admitted as-is and the user is warned instead
+			k8tz.WarningLogger.Printf("skipping pod (%s): %v", formatObjectDetails(pod.ObjectMeta), err)
+			response.Warnings = append(response.Warnings, fmt.Sprintf("k8tz: timezone was not injected, %v", err))
+			return k8tz.Patches{}, nil
+		} else if err != nil {
 			return nil, fmt.Errorf("failed to generate patches for pod, error=%w", err)
 		}
 
@@ -314,26 +585,98 @@ func (h *RequestsHandler) handlePodAdmissionRequest(req *admission.AdmissionRequ
 	return patches, err
 }
 
-func (h *RequestsHandler) handleCronJobAdmissionRequest(req *admission.AdmissionRequest) (k8tz.Patches, error) {
+func (h *RequestsHandler) handleEphemeralContainersAdmissionRequest(req *admission.AdmissionRequest) (k8tz.Patches, error) {
+	pod := corev1.Pod{}
+	if _, _, err := k8sdecode.Decode(req.Object.Raw, nil, &pod); err != nil {
+		return nil, fmt.Errorf("could not deserialize pod object: %v", err)
+	}
+
+	oldPod := corev1.Pod{}
+	if _, _, err := k8sdecode.Decode(req.OldObject.Raw, nil, &oldPod); err != nil {
+		return nil, fmt.Errorf("could not deserialize old pod object: %v", err)
+	}
+
+	generator, _, err := h.lookupEphemeralContainers(req.Namespace, &pod)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lookup generator for ephemeral containers, error=%w", err)
+	}
+
+	if generator == nil {
+		return nil, nil
+	}
+
+	patches, err := generator.GenerateEphemeralContainers(&pod, &oldPod, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate patches for ephemeral containers, error=%w", err)
+	}
+
+	k8tz.InfoLogger.Printf("%d patches generated for ephemeral containers of pod (%s), timezone=%s, strategy=%s", len(patches), formatObjectDetails(pod.ObjectMeta), generator.Timezone, generator.Strategy)
+	return patches, nil
+}
+
+// ensureConfigMap makes sure the ConfigMap mounted by the configMap injection
+// strategy exists in the namespace before the pod is admitted. Dry-run
+// requests must not have side effects, so their ConfigMap is not created.
+func (h *RequestsHandler) ensureConfigMap(namespace string, dryRun *bool, generator *inject.PatchGenerator) error {
+	if generator.Strategy != inject.ConfigMapInjectionStrategy || generator.Timezone == k8tz.HostTimezone {
+		return nil
+	}
+
+	if h.configMaps == nil {
+		return fmt.Errorf("%s injection strategy requires the configmap controller to be enabled", generator.Strategy)
+	}
+
+	if dryRun != nil && *dryRun {
+		k8tz.VerboseLogger.Printf("skipping timezone configmap of namespace %s for a dry-run request", namespace)
+		return nil
+	}
+
+	if err := h.configMaps.Ensure(context.TODO(), namespace, generator.Timezone); err != nil {
+		return fmt.Errorf("failed to ensure timezone configmap, error=%w", err)
+	}
+
+	return nil
+}
+
+func (h *RequestsHandler) handleCronJobAdmissionRequest(req *admission.AdmissionRequest, response *admission.AdmissionResponse) (k8tz.Patches, error) {
 	raw := req.Object.Raw
 	cronJob := batchv1.CronJob{}
 	if _, _, err := k8sdecode.Decode(raw, nil, &cronJob); err != nil {
 		return nil, fmt.Errorf("could not deserialize cronJob object: %v", err)
 	}
 
-	generator, err := h.lookupCronJob(req.Namespace, &cronJob)
+	generator, annotationSources, err := h.lookupCronJob(req.Namespace, &cronJob)
 	if err != nil {
 		return nil, fmt.Errorf("failed to lookup generator for cronJob, error=%w", err)
 	}
 
 	var patches k8tz.Patches
 	if generator != nil {
+		if err := h.enforceAllowedTimezones(req.Namespace, cronJob.ObjectMeta, annotationSources, generator, response); err != nil {
+			return nil, err
+		}
+
+		if generator.CronJobPodTemplate {
+			if err := h.ensureConfigMap(req.Namespace, req.DryRun, generator); err != nil {
+				return nil, err
+			}
+		}
+
 		k8tz.VerboseLogger.Printf("Generating patches for cronJob (%s) using generator: %+v", formatObjectDetails(cronJob.ObjectMeta), *generator)
 		patches, err = generator.Generate(&cronJob, "")
+		if errors.Is(err, inject.ErrPodSecurityViolation) && generator.CronJobPodTemplate {
+			// like pods, the template is left as-is and the user is warned,
+			// the rest of the cronJob (e.g. its timeZone) is still injected
+			k8tz.WarningLogger.Printf("skipping pod template of cronJob (%s): %v", formatObjectDetails(cronJob.ObjectMeta), err)
+			response.Warnings = append(response.Warnings, fmt.Sprintf("k8tz: timezone was not injected to the pod template, %v", err))
+			generator.CronJobPodTemplate = false
+			patches, err = generator.Generate(&cronJob, "")
+		}
 		if err != nil {
-			return nil, fmt.Errorf("failed to generate patches for pod, error=%w", err)
+			return nil, fmt.Errorf("failed to generate patches for cronJob, error=%w", err)
 		}
 
+		response.Warnings = append(response.Warnings, generator.Warnings...)
 		k8tz.InfoLogger.Printf("%d patches generated for cronJob (%s), timezone=%s", len(patches), formatObjectDetails(cronJob.ObjectMeta), generator.Timezone)
 	}
 
@@ -18,22 +18,33 @@ package admission
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	k8tz "github.com/k8tz/k8tz/pkg"
 	"github.com/k8tz/k8tz/pkg/inject"
+	"github.com/k8tz/k8tz/pkg/tzconfigmap"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	admission "k8s.io/api/admission/v1beta1"
 	appsv1 "k8s.io/api/apps/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8sversion "k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
 	"k8s.io/client-go/kubernetes/fake"
 	ktesting "k8s.io/client-go/testing"
 )
@@ -482,6 +493,57 @@ func TestAdmissionRequestsHandler_handleFunc(t *testing.T) {
 				WantCode: http.StatusOK,
 			},
 		},
+		{
+			name: "ephemeral containers of injected pod are injected",
+			fields: fields{
+				DefaultTimezone:          k8tz.UTCTimezone,
+				ContainerName:            "k8tz",
+				BootstrapImage:           "test:0.0.0",
+				DefaultInjectionStrategy: inject.HostPathInjectionStrategy,
+				InjectByDefault:          true,
+				HostPathPrefix:           "/usr/share/zoneinfo",
+				LocalTimePath:            "/etc/localtime",
+				ContentType:              "application/json",
+				Method:                   "POST",
+				ReviewFile:               "testdata/review-ephemeral-containers.json",
+				GoldenFile:               "testdata/review-ephemeral-containers-response.json",
+				FakeObjects: []runtime.Object{
+					&corev1.Namespace{
+						ObjectMeta: v1.ObjectMeta{
+							Name: "default",
+						},
+					},
+				},
+				WantCode: http.StatusOK,
+			},
+		},
+		{
+			name: "hostPath strategy in restricted namespace should warn without injecting",
+			fields: fields{
+				DefaultTimezone:          k8tz.UTCTimezone,
+				ContainerName:            "k8tz",
+				BootstrapImage:           "test:0.0.0",
+				DefaultInjectionStrategy: inject.HostPathInjectionStrategy,
+				InjectByDefault:          true,
+				HostPathPrefix:           "/usr/share/zoneinfo",
+				LocalTimePath:            "/etc/localtime",
+				ContentType:              "application/json",
+				Method:                   "POST",
+				ReviewFile:               "testdata/review-pod.json",
+				GoldenFile:               "testdata/review-pod-restricted-hostpath-response.json",
+				FakeObjects: []runtime.Object{
+					&corev1.Namespace{
+						ObjectMeta: v1.ObjectMeta{
+							Name: "default",
+							Labels: map[string]string{
+								"pod-security.kubernetes.io/enforce": "restricted",
+							},
+						},
+					},
+				},
+				WantCode: http.StatusOK,
+			},
+		},
 		{
 			name: "explicit strategy annotation on namespace",
 			fields: fields{
@@ -827,7 +889,7 @@ func TestRequestsHandler_lookupPodParentAnnotations(t *testing.T) {
 				clientset:                clientset,
 			}
 
-			got, err := h.lookupPod("default", tt.pod)
+			got, _, err := h.lookupPod("default", tt.pod, nil)
 			if err != nil {
 				t.Fatalf("lookupPod() error = %v", err)
 			}
@@ -932,7 +994,7 @@ func TestRequestsHandler_lookupPodParentAnnotationsDisabled(t *testing.T) {
 				clientset:                clientset,
 			}
 
-			got, err := h.lookupPod("default", tt.pod)
+			got, _, err := h.lookupPod("default", tt.pod, nil)
 			if err != nil {
 				t.Fatalf("lookupPod() error = %v", err)
 			}
@@ -952,6 +1014,1624 @@ func TestRequestsHandler_lookupPodParentAnnotationsDisabled(t *testing.T) {
 	}
 }
 
+func TestRequestsHandler_lookupPodServiceAccountAnnotations(t *testing.T) {
+	serviceAccount := &corev1.ServiceAccount{
+		ObjectMeta: v1.ObjectMeta{
+			Name:        "app",
+			Namespace:   "default",
+			Annotations: map[string]string{k8tz.TimezoneAnnotation: "Europe/ServiceAccount"},
+		},
+	}
+	replicaSet := testReplicaSet("rs", map[string]string{k8tz.TimezoneAnnotation: "Europe/ReplicaSet"})
+	namespace := testNamespace(map[string]string{k8tz.TimezoneAnnotation: "Europe/Namespace"})
+
+	tests := []struct {
+		name                 string
+		serviceAccountLookup ServiceAccountLookup
+		serviceAccountName   string
+		objects              []runtime.Object
+		want                 string
+		wantSource           string
+	}{
+		{
+			name:                 "service account is ignored when disabled",
+			serviceAccountLookup: DisabledServiceAccountLookup,
+			serviceAccountName:   "app",
+			objects:              []runtime.Object{namespace, replicaSet, serviceAccount},
+			want:                 "Europe/ReplicaSet",
+			wantSource:           "replicaset",
+		},
+		{
+			name:                 "service account wins over owners before owners",
+			serviceAccountLookup: BeforeOwnersServiceAccountLookup,
+			serviceAccountName:   "app",
+			objects:              []runtime.Object{namespace, replicaSet, serviceAccount},
+			want:                 "Europe/ServiceAccount",
+			wantSource:           "serviceaccount",
+		},
+		{
+			name:                 "owners win over service account after owners",
+			serviceAccountLookup: AfterOwnersServiceAccountLookup,
+			serviceAccountName:   "app",
+			objects:              []runtime.Object{namespace, replicaSet, serviceAccount},
+			want:                 "Europe/ReplicaSet",
+			wantSource:           "replicaset",
+		},
+		{
+			name:                 "service account wins over namespace after owners",
+			serviceAccountLookup: AfterOwnersServiceAccountLookup,
+			serviceAccountName:   "app",
+			objects:              []runtime.Object{namespace, serviceAccount},
+			want:                 "Europe/ServiceAccount",
+			wantSource:           "serviceaccount",
+		},
+		{
+			name:                 "missing service account is skipped",
+			serviceAccountLookup: BeforeOwnersServiceAccountLookup,
+			objects:              []runtime.Object{namespace},
+			want:                 "Europe/Namespace",
+			wantSource:           "namespace",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k8tz.WarningLogger.SetOutput(io.Discard)
+
+			h := &RequestsHandler{
+				DefaultTimezone:          k8tz.UTCTimezone,
+				DefaultInjectionStrategy: inject.InitContainerInjectionStrategy,
+				InjectByDefault:          true,
+				PodOwnerLookup:           true,
+				ServiceAccountLookup:     tt.serviceAccountLookup,
+				clientset:                fake.NewSimpleClientset(tt.objects...),
+			}
+
+			pod := testPod(nil, testOwnerReference("apps/v1", "ReplicaSet", "rs"))
+			pod.Spec.ServiceAccountName = tt.serviceAccountName
+
+			got, sources, err := h.lookupPod("default", pod, nil)
+			if err != nil {
+				t.Fatalf("lookupPod() error = %v", err)
+			}
+			if got.Timezone != tt.want {
+				t.Errorf("lookupPod().Timezone = %s, want %s", got.Timezone, tt.want)
+			}
+
+			response := &admission.AdmissionResponse{}
+			setAnnotationSourcesAuditAnnotation(response, sources, nil)
+			want := fmt.Sprintf("timezone=%s,strategy=default,inject=default", tt.wantSource)
+			if got := response.AuditAnnotations[AnnotationSourcesAuditAnnotation]; got != want {
+				t.Errorf("audit annotation = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestRequestsHandler_lookupPodLabels(t *testing.T) {
+	tests := []struct {
+		name               string
+		metadataPrecedence MetadataPrecedence
+		podAnnotations     map[string]string
+		podLabels          map[string]string
+		namespaceLabels    map[string]string
+		wantNil            bool
+		wantTimezone       string
+		wantStrategy       inject.InjectionStrategy
+	}{
+		{
+			name:         "timezone label is decoded",
+			podLabels:    map[string]string{k8tz.TimezoneAnnotation: "America_Argentina_Buenos__Aires"},
+			wantTimezone: "America/Argentina/Buenos_Aires",
+			wantStrategy: inject.InitContainerInjectionStrategy,
+		},
+		{
+			name:            "namespace labels are inherited",
+			namespaceLabels: map[string]string{k8tz.TimezoneAnnotation: "Europe_London", k8tz.InjectionStrategyAnnotation: "hostPath"},
+			wantTimezone:    "Europe/London",
+			wantStrategy:    inject.HostPathInjectionStrategy,
+		},
+		{
+			name:            "pod annotation wins over namespace label",
+			podAnnotations:  map[string]string{k8tz.TimezoneAnnotation: "Asia/Tokyo"},
+			namespaceLabels: map[string]string{k8tz.TimezoneAnnotation: "Europe_London"},
+			wantTimezone:    "Asia/Tokyo",
+			wantStrategy:    inject.InitContainerInjectionStrategy,
+		},
+		{
+			name:           "annotation wins over label by default",
+			podAnnotations: map[string]string{k8tz.TimezoneAnnotation: "Asia/Tokyo"},
+			podLabels:      map[string]string{k8tz.TimezoneAnnotation: "Europe_London"},
+			wantTimezone:   "Asia/Tokyo",
+			wantStrategy:   inject.InitContainerInjectionStrategy,
+		},
+		{
+			name:               "label wins over annotation with labels precedence",
+			metadataPrecedence: LabelsMetadataPrecedence,
+			podAnnotations:     map[string]string{k8tz.TimezoneAnnotation: "Asia/Tokyo"},
+			podLabels:          map[string]string{k8tz.TimezoneAnnotation: "Europe_London"},
+			wantTimezone:       "Europe/London",
+			wantStrategy:       inject.InitContainerInjectionStrategy,
+		},
+		{
+			name:      "inject label disables injection",
+			podLabels: map[string]string{k8tz.InjectAnnotation: "false"},
+			wantNil:   true,
+		},
+		{
+			name:         "other labels are ignored",
+			podLabels:    map[string]string{k8tz.RuntimeProfileAnnotation: "java"},
+			wantTimezone: k8tz.UTCTimezone,
+			wantStrategy: inject.InitContainerInjectionStrategy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			namespace := testNamespace(nil)
+			namespace.Labels = tt.namespaceLabels
+
+			h := &RequestsHandler{
+				DefaultTimezone:          k8tz.UTCTimezone,
+				DefaultInjectionStrategy: inject.InitContainerInjectionStrategy,
+				InjectByDefault:          true,
+				MetadataPrecedence:       tt.metadataPrecedence,
+				clientset:                fake.NewSimpleClientset(namespace),
+			}
+
+			pod := testPod(tt.podAnnotations)
+			pod.Labels = tt.podLabels
+
+			got, _, err := h.lookupPod("default", pod, nil)
+			if err != nil {
+				t.Fatalf("lookupPod() error = %v", err)
+			}
+			if tt.wantNil {
+				if got != nil {
+					t.Errorf("lookupPod() = %v, want nil", got)
+				}
+				return
+			}
+			if got.Timezone != tt.wantTimezone {
+				t.Errorf("lookupPod().Timezone = %s, want %s", got.Timezone, tt.wantTimezone)
+			}
+			if got.Strategy != tt.wantStrategy {
+				t.Errorf("lookupPod().Strategy = %s, want %s", got.Strategy, tt.wantStrategy)
+			}
+			if got.RuntimeProfile != "" {
+				t.Errorf("lookupPod().RuntimeProfile = %s, want empty", got.RuntimeProfile)
+			}
+		})
+	}
+}
+
+func TestRequestsHandler_lookupPodNamespaceHierarchy(t *testing.T) {
+	namespaceWithLabels := func(name string, annotations map[string]string, labels map[string]string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: v1.ObjectMeta{Name: name, Annotations: annotations, Labels: labels}}
+	}
+
+	tests := []struct {
+		name        string
+		hierarchy   NamespaceHierarchy
+		parentLabel string
+		objects     []runtime.Object
+		want        string
+	}{
+		{
+			name:      "ancestors are ignored when disabled",
+			hierarchy: DisabledNamespaceHierarchy,
+			objects: []runtime.Object{
+				namespaceWithLabels("default", nil, map[string]string{"team.tree.hnc.x-k8s.io/depth": "1"}),
+				namespaceWithLabels("team", map[string]string{k8tz.TimezoneAnnotation: "Europe/Team"}, nil),
+			},
+			want: k8tz.UTCTimezone,
+		},
+		{
+			name:      "closest hnc ancestor wins",
+			hierarchy: HNCNamespaceHierarchy,
+			objects: []runtime.Object{
+				namespaceWithLabels("default", nil, map[string]string{
+					"default.tree.hnc.x-k8s.io/depth": "0",
+					"team.tree.hnc.x-k8s.io/depth":    "1",
+					"org.tree.hnc.x-k8s.io/depth":     "2",
+				}),
+				namespaceWithLabels("team", map[string]string{k8tz.TimezoneAnnotation: "Europe/Team"}, nil),
+				namespaceWithLabels("org", map[string]string{k8tz.TimezoneAnnotation: "Europe/Org"}, nil),
+			},
+			want: "Europe/Team",
+		},
+		{
+			name:      "namespace wins over hnc ancestors",
+			hierarchy: HNCNamespaceHierarchy,
+			objects: []runtime.Object{
+				namespaceWithLabels("default", map[string]string{k8tz.TimezoneAnnotation: "Europe/Namespace"}, map[string]string{"team.tree.hnc.x-k8s.io/depth": "1"}),
+				namespaceWithLabels("team", map[string]string{k8tz.TimezoneAnnotation: "Europe/Team"}, nil),
+			},
+			want: "Europe/Namespace",
+		},
+		{
+			name:      "missing hnc ancestor is skipped",
+			hierarchy: HNCNamespaceHierarchy,
+			objects: []runtime.Object{
+				namespaceWithLabels("default", nil, map[string]string{
+					"team.tree.hnc.x-k8s.io/depth": "1",
+					"org.tree.hnc.x-k8s.io/depth":  "2",
+				}),
+				namespaceWithLabels("org", map[string]string{k8tz.TimezoneAnnotation: "Europe/Org"}, nil),
+			},
+			want: "Europe/Org",
+		},
+		{
+			name:        "parent label is followed",
+			hierarchy:   LabelNamespaceHierarchy,
+			parentLabel: "example.com/parent",
+			objects: []runtime.Object{
+				namespaceWithLabels("default", nil, map[string]string{"example.com/parent": "team"}),
+				namespaceWithLabels("team", nil, map[string]string{"example.com/parent": "org"}),
+				namespaceWithLabels("org", map[string]string{k8tz.TimezoneAnnotation: "Europe/Org"}, nil),
+			},
+			want: "Europe/Org",
+		},
+		{
+			name:        "parent label loop stops the lookup",
+			hierarchy:   LabelNamespaceHierarchy,
+			parentLabel: "example.com/parent",
+			objects: []runtime.Object{
+				namespaceWithLabels("default", nil, map[string]string{"example.com/parent": "team"}),
+				namespaceWithLabels("team", nil, map[string]string{"example.com/parent": "default"}),
+			},
+			want: k8tz.UTCTimezone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k8tz.WarningLogger.SetOutput(io.Discard)
+
+			h := &RequestsHandler{
+				DefaultTimezone:          k8tz.UTCTimezone,
+				DefaultInjectionStrategy: inject.InitContainerInjectionStrategy,
+				InjectByDefault:          true,
+				NamespaceHierarchy:       tt.hierarchy,
+				NamespaceParentLabel:     tt.parentLabel,
+				clientset:                fake.NewSimpleClientset(tt.objects...),
+			}
+
+			got, _, err := h.lookupPod("default", testPod(nil), nil)
+			if err != nil {
+				t.Fatalf("lookupPod() error = %v", err)
+			}
+			if got.Timezone != tt.want {
+				t.Errorf("lookupPod().Timezone = %s, want %s", got.Timezone, tt.want)
+			}
+
+			cronJob, _, err := h.lookupCronJob("default", testCronJob("cronjob", nil))
+			if err != nil {
+				t.Fatalf("lookupCronJob() error = %v", err)
+			}
+			if cronJob.Timezone != tt.want {
+				t.Errorf("lookupCronJob().Timezone = %s, want %s", cronJob.Timezone, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestsHandler_checkNamespaceHierarchy(t *testing.T) {
+	tests := []struct {
+		name        string
+		hierarchy   NamespaceHierarchy
+		parentLabel string
+		wantErr     bool
+	}{
+		{name: "disabled", hierarchy: DisabledNamespaceHierarchy},
+		{name: "hnc", hierarchy: HNCNamespaceHierarchy},
+		{name: "label", hierarchy: LabelNamespaceHierarchy, parentLabel: "example.com/parent"},
+		{name: "label without parent label", hierarchy: LabelNamespaceHierarchy, wantErr: true},
+		{name: "unknown", hierarchy: "capsule", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &RequestsHandler{NamespaceHierarchy: tt.hierarchy, NamespaceParentLabel: tt.parentLabel}
+			if err := h.checkNamespaceHierarchy(); (err != nil) != tt.wantErr {
+				t.Errorf("checkNamespaceHierarchy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRequestsHandler_lookupPodZoneinfoPath(t *testing.T) {
+	tests := []struct {
+		name             string
+		pod              *corev1.Pod
+		objects          []runtime.Object
+		allowedOverrides []string
+		want             string
+	}{
+		{
+			name:    "handler default is used without annotations",
+			pod:     testPod(nil),
+			objects: []runtime.Object{testNamespace(nil)},
+			want:    inject.DefaultZoneinfoMountPath,
+		},
+		{
+			name: "namespace annotation overrides handler default",
+			pod:  testPod(nil),
+			objects: []runtime.Object{testNamespace(map[string]string{
+				k8tz.ZoneinfoPathAnnotation: inject.NoZoneinfoMountPath,
+			})},
+			want: inject.NoZoneinfoMountPath,
+		},
+		{
+			name: "pod annotation wins over namespace annotation",
+			pod: testPod(map[string]string{
+				k8tz.ZoneinfoPathAnnotation: "/opt/zoneinfo",
+			}),
+			objects: []runtime.Object{testNamespace(map[string]string{
+				k8tz.ZoneinfoPathAnnotation: inject.NoZoneinfoMountPath,
+			})},
+			allowedOverrides: []string{"zoneinfo-path"},
+			want:             "/opt/zoneinfo",
+		},
+		{
+			name: "pod annotation is ignored when the override is not allowed",
+			pod: testPod(map[string]string{
+				k8tz.ZoneinfoPathAnnotation: "/opt/zoneinfo",
+			}),
+			objects: []runtime.Object{testNamespace(map[string]string{
+				k8tz.ZoneinfoPathAnnotation: inject.NoZoneinfoMountPath,
+			})},
+			want: inject.NoZoneinfoMountPath,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &RequestsHandler{
+				DefaultTimezone:          k8tz.UTCTimezone,
+				DefaultInjectionStrategy: inject.InitContainerInjectionStrategy,
+				InjectByDefault:          true,
+				ZoneinfoMountPath:        inject.DefaultZoneinfoMountPath,
+				AllowedOverrides:         tt.allowedOverrides,
+				clientset:                fake.NewSimpleClientset(tt.objects...),
+			}
+
+			got, _, err := h.lookupPod("default", tt.pod, nil)
+			if err != nil {
+				t.Fatalf("lookupPod() error = %v", err)
+			}
+			if got == nil {
+				t.Fatal("lookupPod() = nil, want generator")
+			}
+			if got.ZoneinfoMountPath != tt.want {
+				t.Errorf("lookupPod().ZoneinfoMountPath = %s, want %s", got.ZoneinfoMountPath, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestsHandler_lookupPodOverrides(t *testing.T) {
+	tests := []struct {
+		name             string
+		allowedOverrides []string
+		pod              *corev1.Pod
+		objects          []runtime.Object
+		wantImage        string
+		wantSizeLimit    string
+		wantErr          bool
+	}{
+		{
+			name:      "handler defaults are used without annotations",
+			pod:       testPod(nil),
+			objects:   []runtime.Object{testNamespace(nil)},
+			wantImage: "test:0.0.0",
+		},
+		{
+			name: "pod overrides are ignored when not allowed",
+			pod: testPod(map[string]string{
+				k8tz.BootstrapImageAnnotation:    "tenant:1.0.0",
+				k8tz.EmptyDirSizeLimitAnnotation: "1Mi",
+			}),
+			objects:   []runtime.Object{testNamespace(nil)},
+			wantImage: "test:0.0.0",
+		},
+		{
+			name:             "allowed pod overrides are applied",
+			allowedOverrides: []string{"bootstrap-image"},
+			pod: testPod(map[string]string{
+				k8tz.BootstrapImageAnnotation:    "tenant:1.0.0",
+				k8tz.EmptyDirSizeLimitAnnotation: "1Mi",
+			}),
+			objects:   []runtime.Object{testNamespace(nil)},
+			wantImage: "tenant:1.0.0",
+		},
+		{
+			name:             "all pod overrides are applied with wildcard",
+			allowedOverrides: []string{AllOverrides},
+			pod: testPod(map[string]string{
+				k8tz.BootstrapImageAnnotation:    "tenant:1.0.0",
+				k8tz.EmptyDirSizeLimitAnnotation: "1Mi",
+			}),
+			objects:       []runtime.Object{testNamespace(nil)},
+			wantImage:     "tenant:1.0.0",
+			wantSizeLimit: "1Mi",
+		},
+		{
+			name: "namespace overrides are applied when not allowed",
+			pod: testPod(map[string]string{
+				k8tz.BootstrapImageAnnotation: "tenant:1.0.0",
+			}),
+			objects: []runtime.Object{testNamespace(map[string]string{
+				k8tz.BootstrapImageAnnotation: "mirror:0.0.0",
+			})},
+			wantImage: "mirror:0.0.0",
+		},
+		{
+			name:             "invalid override value should raise an error",
+			allowedOverrides: []string{"bootstrap-verbose"},
+			pod: testPod(map[string]string{
+				k8tz.BootstrapVerboseAnnotation: "sometimes",
+			}),
+			objects: []runtime.Object{testNamespace(nil)},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k8tz.WarningLogger.SetOutput(io.Discard)
+
+			h := &RequestsHandler{
+				DefaultTimezone:          k8tz.UTCTimezone,
+				BootstrapImage:           "test:0.0.0",
+				DefaultInjectionStrategy: inject.InitContainerInjectionStrategy,
+				InjectByDefault:          true,
+				AllowedOverrides:         tt.allowedOverrides,
+				clientset:                fake.NewSimpleClientset(tt.objects...),
+			}
+
+			got, _, err := h.lookupPod("default", tt.pod, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("lookupPod() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got == nil {
+				t.Fatal("lookupPod() = nil, want generator")
+			}
+			if got.InitContainerImage != tt.wantImage {
+				t.Errorf("lookupPod().InitContainerImage = %s, want %s", got.InitContainerImage, tt.wantImage)
+			}
+			if got.EmptyDirSizeLimit != tt.wantSizeLimit {
+				t.Errorf("lookupPod().EmptyDirSizeLimit = %s, want %s", got.EmptyDirSizeLimit, tt.wantSizeLimit)
+			}
+		})
+	}
+}
+
+func TestRequestsHandler_lookupPodReinvocation(t *testing.T) {
+	injectedPod := testPod(map[string]string{
+		k8tz.InjectedAnnotation:         "true",
+		k8tz.InjectedStrategyAnnotation: string(inject.ImageVolumeInjectionStrategy),
+	})
+
+	tests := []struct {
+		name         string
+		reinvocation bool
+		wantNil      bool
+		wantStrategy inject.InjectionStrategy
+	}{
+		{
+			name:    "injected pod is skipped without reinvocation",
+			wantNil: true,
+		},
+		{
+			name:         "injected pod is reinjected with the injected strategy",
+			reinvocation: true,
+			wantStrategy: inject.ImageVolumeInjectionStrategy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &RequestsHandler{
+				DefaultTimezone:          k8tz.UTCTimezone,
+				DefaultInjectionStrategy: inject.AutoInjectionStrategy,
+				InjectByDefault:          true,
+				Reinvocation:             tt.reinvocation,
+				clientset:                fake.NewSimpleClientset(testNamespace(nil)),
+			}
+
+			got, _, err := h.lookupPod("default", injectedPod, nil)
+			if err != nil {
+				t.Fatalf("lookupPod() error = %v", err)
+			}
+			if tt.wantNil {
+				if got != nil {
+					t.Fatalf("lookupPod() = %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatal("lookupPod() = nil, want generator")
+			}
+			if !got.Reinvocation {
+				t.Error("lookupPod().Reinvocation = false, want true")
+			}
+			if got.Strategy != tt.wantStrategy {
+				t.Errorf("lookupPod().Strategy = %s, want %s", got.Strategy, tt.wantStrategy)
+			}
+			if got.AnnotateStrategy {
+				t.Error("lookupPod().AnnotateStrategy = true, want false")
+			}
+		})
+	}
+}
+
+func TestRequestsHandler_ensureConfigMap(t *testing.T) {
+	zoneinfo := t.TempDir()
+	if err := os.WriteFile(filepath.Join(zoneinfo, "UTC"), []byte("TZif-utc"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	clientset := fake.NewSimpleClientset()
+	configMaps := tzconfigmap.NewController(clientset)
+	configMaps.ZoneinfoPath = zoneinfo
+
+	generator := &inject.PatchGenerator{
+		Strategy: inject.ConfigMapInjectionStrategy,
+		Timezone: "UTC",
+	}
+
+	h := &RequestsHandler{clientset: clientset}
+	if err := h.ensureConfigMap("default", nil, generator); err == nil {
+		t.Error("ensureConfigMap() without configmap controller should return an error")
+	}
+
+	h.configMaps = configMaps
+	dryRun := true
+	if err := h.ensureConfigMap("default", &dryRun, generator); err != nil {
+		t.Fatalf("ensureConfigMap() dry-run error = %v", err)
+	}
+	if _, err := clientset.CoreV1().ConfigMaps("default").Get(context.TODO(), inject.ConfigMapName("UTC"), v1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("ensureConfigMap() created the configmap of a dry-run request: %v", err)
+	}
+
+	if err := h.ensureConfigMap("default", nil, generator); err != nil {
+		t.Fatalf("ensureConfigMap() error = %v", err)
+	}
+
+	if _, err := clientset.CoreV1().ConfigMaps("default").Get(context.TODO(), inject.ConfigMapName("UTC"), v1.GetOptions{}); err != nil {
+		t.Errorf("ensureConfigMap() did not create the configmap: %v", err)
+	}
+}
+
+func TestRequestsHandler_lookupCronJobStrategy(t *testing.T) {
+	tests := []struct {
+		name      string
+		cronJob   *batchv1.CronJob
+		namespace *corev1.Namespace
+		want      inject.InjectionStrategy
+	}{
+		{
+			name:      "handler default is used without annotations",
+			cronJob:   &batchv1.CronJob{ObjectMeta: v1.ObjectMeta{Name: "cronjob", Namespace: "default"}},
+			namespace: testNamespace(nil),
+			want:      inject.InitContainerInjectionStrategy,
+		},
+		{
+			name:      "namespace annotation overrides handler default",
+			cronJob:   &batchv1.CronJob{ObjectMeta: v1.ObjectMeta{Name: "cronjob", Namespace: "default"}},
+			namespace: testNamespace(map[string]string{k8tz.InjectionStrategyAnnotation: string(inject.EnvInjectionStrategy)}),
+			want:      inject.EnvInjectionStrategy,
+		},
+		{
+			name: "cronJob annotation wins over namespace annotation",
+			cronJob: &batchv1.CronJob{ObjectMeta: v1.ObjectMeta{Name: "cronjob", Namespace: "default", Annotations: map[string]string{
+				k8tz.InjectionStrategyAnnotation: string(inject.EnvInjectionStrategy),
+			}}},
+			namespace: testNamespace(map[string]string{k8tz.InjectionStrategyAnnotation: string(inject.HostPathInjectionStrategy)}),
+			want:      inject.EnvInjectionStrategy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &RequestsHandler{
+				DefaultTimezone:          k8tz.UTCTimezone,
+				DefaultInjectionStrategy: inject.InitContainerInjectionStrategy,
+				InjectByDefault:          true,
+				clientset:                fake.NewSimpleClientset(tt.namespace),
+			}
+
+			got, _, err := h.lookupCronJob("default", tt.cronJob)
+			if err != nil {
+				t.Fatalf("lookupCronJob() error = %v", err)
+			}
+			if got.Strategy != tt.want {
+				t.Errorf("lookupCronJob().Strategy = %s, want %s", got.Strategy, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestsHandler_lookupCronJobOverrides(t *testing.T) {
+	tests := []struct {
+		name             string
+		cronJob          *batchv1.CronJob
+		namespace        *corev1.Namespace
+		allowedOverrides []string
+		want             string
+	}{
+		{
+			name:      "namespace override is honored",
+			cronJob:   testCronJob("cronjob", nil),
+			namespace: testNamespace(map[string]string{k8tz.TzdataImageAnnotation: "tzdata:namespace"}),
+			want:      "tzdata:namespace",
+		},
+		{
+			name:      "cronJob override is ignored when not allowed",
+			cronJob:   testCronJob("cronjob", map[string]string{k8tz.TzdataImageAnnotation: "tzdata:cronjob"}),
+			namespace: testNamespace(nil),
+			want:      "tzdata:default",
+		},
+		{
+			name:             "cronJob override is honored when allowed",
+			cronJob:          testCronJob("cronjob", map[string]string{k8tz.TzdataImageAnnotation: "tzdata:cronjob"}),
+			namespace:        testNamespace(nil),
+			allowedOverrides: []string{"tzdata-image"},
+			want:             "tzdata:cronjob",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &RequestsHandler{
+				DefaultTimezone:          k8tz.UTCTimezone,
+				DefaultInjectionStrategy: inject.ImageVolumeInjectionStrategy,
+				InjectByDefault:          true,
+				TzdataImage:              "tzdata:default",
+				AllowedOverrides:         tt.allowedOverrides,
+				clientset:                fake.NewSimpleClientset(tt.namespace),
+			}
+
+			got, _, err := h.lookupCronJob("default", tt.cronJob)
+			if err != nil {
+				t.Fatalf("lookupCronJob() error = %v", err)
+			}
+			if got.TzdataImage != tt.want {
+				t.Errorf("lookupCronJob().TzdataImage = %s, want %s", got.TzdataImage, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestsHandler_detectImageVolumeLocalTime(t *testing.T) {
+	tests := []struct {
+		name          string
+		serverVersion string
+		want          bool
+	}{
+		{name: "older server", serverVersion: "v1.33.2", want: false},
+		{name: "minimal server version", serverVersion: "v1.35.0", want: true},
+		{name: "newer server with vendor suffix", serverVersion: "v1.36.1-eks-1234", want: true},
+		{name: "unparsable server version", serverVersion: "unknown", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset()
+			clientset.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &k8sversion.Info{GitVersion: tt.serverVersion}
+
+			h := &RequestsHandler{
+				ImageVolumeLocalTimeVersion: inject.ImageVolumeLocalTimeMinVersion,
+				clientset:                   clientset,
+			}
+			h.detectImageVolumeLocalTime()
+
+			if h.imageVolumeLocalTime != tt.want {
+				t.Errorf("detectImageVolumeLocalTime() = %t, want %t", h.imageVolumeLocalTime, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestsHandler_resolveAutoStrategy(t *testing.T) {
+	restricted := &corev1.Namespace{ObjectMeta: v1.ObjectMeta{
+		Name:   "default",
+		Labels: map[string]string{"pod-security.kubernetes.io/enforce": "restricted"},
+	}}
+
+	tests := []struct {
+		name                 string
+		strategies           []string
+		imageVolumeLocalTime bool
+		namespace            *corev1.Namespace
+		want                 inject.InjectionStrategy
+		wantErr              bool
+	}{
+		{
+			name:                 "imageVolume when supported by the server",
+			strategies:           DefaultAutoStrategies,
+			imageVolumeLocalTime: true,
+			namespace:            testNamespace(nil),
+			want:                 inject.ImageVolumeInjectionStrategy,
+		},
+		{
+			name:       "falls back when imageVolume is not supported",
+			strategies: DefaultAutoStrategies,
+			namespace:  testNamespace(nil),
+			want:       inject.InitContainerInjectionStrategy,
+		},
+		{
+			name:       "hostPath in namespace without pod security",
+			strategies: []string{"hostPath", "initContainer"},
+			namespace:  testNamespace(nil),
+			want:       inject.HostPathInjectionStrategy,
+		},
+		{
+			name:       "hostPath is skipped in restricted namespace",
+			strategies: []string{"hostPath", "initContainer"},
+			namespace:  restricted,
+			want:       inject.InitContainerInjectionStrategy,
+		},
+		{
+			name:       "configMap is skipped without controller",
+			strategies: []string{"configMap", "env"},
+			namespace:  testNamespace(nil),
+			want:       inject.EnvInjectionStrategy,
+		},
+		{
+			name:       "no supported strategy",
+			strategies: []string{"hostPath", "moo"},
+			namespace:  restricted,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &RequestsHandler{
+				AutoStrategies:       tt.strategies,
+				imageVolumeLocalTime: tt.imageVolumeLocalTime,
+			}
+
+			got, err := h.resolveAutoStrategy(tt.namespace)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveAutoStrategy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("resolveAutoStrategy() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestsHandler_lookupPodAutoStrategy(t *testing.T) {
+	h := &RequestsHandler{
+		DefaultTimezone:          k8tz.UTCTimezone,
+		DefaultInjectionStrategy: inject.AutoInjectionStrategy,
+		InjectByDefault:          true,
+		AutoStrategies:           DefaultAutoStrategies,
+		clientset:                fake.NewSimpleClientset(testNamespace(nil)),
+	}
+
+	got, _, err := h.lookupPod("default", testPod(nil), nil)
+	if err != nil {
+		t.Fatalf("lookupPod() error = %v", err)
+	}
+	if got.Strategy != inject.InitContainerInjectionStrategy || !got.AnnotateStrategy {
+		t.Errorf("lookupPod() strategy = %s, annotate = %t, want %s annotated", got.Strategy, got.AnnotateStrategy, inject.InitContainerInjectionStrategy)
+	}
+}
+
+func TestRequestsHandler_matchTimezoneRule(t *testing.T) {
+	h := &RequestsHandler{
+		TimezoneRules: []string{
+			"user:alice=Europe/London",
+			"serviceaccount:batch/apac-runner=Asia/Tokyo",
+			"group:emea=Europe/Berlin",
+		},
+	}
+	if err := h.parseTimezoneRules(); err != nil {
+		t.Fatalf("parseTimezoneRules() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		userInfo authenticationv1.UserInfo
+		want     string
+	}{
+		{
+			name:     "user rule matches username",
+			userInfo: authenticationv1.UserInfo{Username: "alice", Groups: []string{"emea"}},
+			want:     "user:alice=Europe/London",
+		},
+		{
+			name:     "service account rule matches service account username",
+			userInfo: authenticationv1.UserInfo{Username: "system:serviceaccount:batch:apac-runner"},
+			want:     "serviceaccount:batch/apac-runner=Asia/Tokyo",
+		},
+		{
+			name:     "group rule matches any group",
+			userInfo: authenticationv1.UserInfo{Username: "bob", Groups: []string{"system:authenticated", "emea"}},
+			want:     "group:emea=Europe/Berlin",
+		},
+		{
+			name:     "no rule matches",
+			userInfo: authenticationv1.UserInfo{Username: "carol", Groups: []string{"system:authenticated"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ""
+			if rule := h.matchTimezoneRule(tt.userInfo); rule != nil {
+				got = rule.String()
+			}
+			if got != tt.want {
+				t.Errorf("matchTimezoneRule() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestsHandler_parseTimezoneRulesInvalid(t *testing.T) {
+	for _, rule := range []string{"alice=Europe/London", "user:alice", "team:emea=Europe/Berlin", "serviceaccount:runner=Asia/Tokyo"} {
+		h := &RequestsHandler{TimezoneRules: []string{rule}}
+		if err := h.parseTimezoneRules(); err == nil {
+			t.Errorf("parseTimezoneRules(%q) error = nil, want error", rule)
+		}
+	}
+}
+
+func TestRequestsHandler_lookupPodTimezoneRule(t *testing.T) {
+	rule := &timezoneRule{Kind: "group", Name: "emea", Timezone: "Europe/Berlin"}
+
+	tests := []struct {
+		name    string
+		pod     *corev1.Pod
+		objects []runtime.Object
+		rule    *timezoneRule
+		want    string
+	}{
+		{
+			name:    "rule wins over namespace annotation",
+			pod:     testPod(nil),
+			objects: []runtime.Object{testNamespace(map[string]string{k8tz.TimezoneAnnotation: "Asia/Jerusalem"})},
+			rule:    rule,
+			want:    "Europe/Berlin",
+		},
+		{
+			name:    "pod annotation wins over rule",
+			pod:     testPod(map[string]string{k8tz.TimezoneAnnotation: "Europe/London"}),
+			objects: []runtime.Object{testNamespace(nil)},
+			rule:    rule,
+			want:    "Europe/London",
+		},
+		{
+			name:    "namespace annotation is used without rule",
+			pod:     testPod(nil),
+			objects: []runtime.Object{testNamespace(map[string]string{k8tz.TimezoneAnnotation: "Asia/Jerusalem"})},
+			want:    "Asia/Jerusalem",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &RequestsHandler{
+				DefaultTimezone:          k8tz.UTCTimezone,
+				DefaultInjectionStrategy: inject.InitContainerInjectionStrategy,
+				InjectByDefault:          true,
+				clientset:                fake.NewSimpleClientset(tt.objects...),
+			}
+
+			got, _, err := h.lookupPod("default", tt.pod, tt.rule)
+			if err != nil {
+				t.Fatalf("lookupPod() error = %v", err)
+			}
+			if got.Timezone != tt.want {
+				t.Errorf("lookupPod().Timezone = %s, want %s", got.Timezone, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestsHandler_lookupPodAutoTimezone(t *testing.T) {
+	requiredAffinity := func(terms ...[]corev1.NodeSelectorRequirement) *corev1.Affinity {
+		selector := &corev1.NodeSelector{}
+		for _, term := range terms {
+			selector.NodeSelectorTerms = append(selector.NodeSelectorTerms, corev1.NodeSelectorTerm{MatchExpressions: term})
+		}
+		return &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{RequiredDuringSchedulingIgnoredDuringExecution: selector}}
+	}
+	in := func(key string, values ...string) corev1.NodeSelectorRequirement {
+		return corev1.NodeSelectorRequirement{Key: key, Operator: corev1.NodeSelectorOpIn, Values: values}
+	}
+
+	tests := []struct {
+		name         string
+		namespace    map[string]string
+		nodeSelector map[string]string
+		affinity     *corev1.Affinity
+		want         string
+	}{
+		{
+			name:         "region node selector",
+			nodeSelector: map[string]string{corev1.LabelTopologyRegion: "eu-west-2"},
+			want:         "Europe/London",
+		},
+		{
+			name:         "zone wins over region",
+			nodeSelector: map[string]string{corev1.LabelTopologyRegion: "eu-west-2", corev1.LabelTopologyZone: "eu-west-2-dublin"},
+			want:         "Europe/Dublin",
+		},
+		{
+			name:     "regions of the same timezone",
+			affinity: requiredAffinity([]corev1.NodeSelectorRequirement{in(corev1.LabelTopologyRegion, "us-east-1", "us-east-2")}),
+			want:     "America/New_York",
+		},
+		{
+			name: "terms of the same timezone",
+			affinity: requiredAffinity(
+				[]corev1.NodeSelectorRequirement{in(corev1.LabelTopologyRegion, "us-east-1")},
+				[]corev1.NodeSelectorRequirement{in(corev1.LabelTopologyZone, "us-east-2a")},
+			),
+			want: "America/New_York",
+		},
+		{
+			name:      "regions of different timezones fall back",
+			namespace: map[string]string{k8tz.TimezoneAnnotation: "Asia/Tokyo"},
+			affinity:  requiredAffinity([]corev1.NodeSelectorRequirement{in(corev1.LabelTopologyRegion, "us-east-1", "eu-west-2")}),
+			want:      "Asia/Tokyo",
+		},
+		{
+			name:     "repeated requirements are intersected",
+			affinity: requiredAffinity([]corev1.NodeSelectorRequirement{in(corev1.LabelTopologyRegion, "us-east-1", "eu-west-2"), in(corev1.LabelTopologyRegion, "eu-west-2")}),
+			want:     "Europe/London",
+		},
+		{
+			name:         "node selector narrows the affinity",
+			nodeSelector: map[string]string{corev1.LabelTopologyRegion: "eu-west-2"},
+			affinity:     requiredAffinity([]corev1.NodeSelectorRequirement{in(corev1.LabelTopologyRegion, "us-east-1", "eu-west-2")}),
+			want:         "Europe/London",
+		},
+		{
+			name:     "disjoint requirements fall back",
+			affinity: requiredAffinity([]corev1.NodeSelectorRequirement{in(corev1.LabelTopologyRegion, "us-east-1"), in(corev1.LabelTopologyRegion, "eu-west-2")}),
+			want:     k8tz.UTCTimezone,
+		},
+		{
+			name: "unconstrained term falls back",
+			affinity: requiredAffinity(
+				[]corev1.NodeSelectorRequirement{in(corev1.LabelTopologyRegion, "eu-west-2")},
+				[]corev1.NodeSelectorRequirement{in("kubernetes.io/arch", "arm64")},
+			),
+			want: k8tz.UTCTimezone,
+		},
+		{
+			name:         "unmapped region falls back",
+			nodeSelector: map[string]string{corev1.LabelTopologyRegion: "ap-south-1"},
+			want:         k8tz.UTCTimezone,
+		},
+		{
+			name: "unconstrained pod falls back",
+			want: k8tz.UTCTimezone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &RequestsHandler{
+				DefaultTimezone:          k8tz.UTCTimezone,
+				DefaultInjectionStrategy: inject.InitContainerInjectionStrategy,
+				InjectByDefault:          true,
+				RegionTimezones:          []string{"eu-west-2=Europe/London", "eu-west-2-dublin=Europe/Dublin", "us-east-1=America/New_York", "us-east-2=America/New_York", "us-east-2a=America/New_York"},
+				clientset:                fake.NewSimpleClientset(testNamespace(tt.namespace)),
+			}
+			if err := h.parseRegionTimezones(); err != nil {
+				t.Fatalf("parseRegionTimezones() error = %v", err)
+			}
+
+			pod := testPod(map[string]string{k8tz.TimezoneAnnotation: k8tz.AutoTimezone})
+			pod.Spec.NodeSelector = tt.nodeSelector
+			pod.Spec.Affinity = tt.affinity
+
+			got, _, err := h.lookupPod("default", pod, nil)
+			if err != nil {
+				t.Fatalf("lookupPod() error = %v", err)
+			}
+			if got.Timezone != tt.want {
+				t.Errorf("lookupPod().Timezone = %s, want %s", got.Timezone, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestsHandler_lookupCronJobAutoTimezone(t *testing.T) {
+	h := &RequestsHandler{
+		DefaultTimezone:          k8tz.UTCTimezone,
+		DefaultInjectionStrategy: inject.InitContainerInjectionStrategy,
+		InjectByDefault:          true,
+		RegionTimezones:          []string{"eu-west-2=Europe/London"},
+		clientset:                fake.NewSimpleClientset(testNamespace(map[string]string{k8tz.TimezoneAnnotation: "Asia/Tokyo"})),
+	}
+	if err := h.parseRegionTimezones(); err != nil {
+		t.Fatalf("parseRegionTimezones() error = %v", err)
+	}
+
+	cronJob := testCronJob("hello", map[string]string{k8tz.TimezoneAnnotation: k8tz.AutoTimezone})
+	got, _, err := h.lookupCronJob("default", cronJob)
+	if err != nil {
+		t.Fatalf("lookupCronJob() error = %v", err)
+	}
+	if got.Timezone != "Asia/Tokyo" {
+		t.Errorf("lookupCronJob().Timezone = %s, want namespace timezone Asia/Tokyo", got.Timezone)
+	}
+
+	cronJob.Spec.JobTemplate.Spec.Template.Spec.NodeSelector = map[string]string{corev1.LabelTopologyRegion: "eu-west-2"}
+	got, _, err = h.lookupCronJob("default", cronJob)
+	if err != nil {
+		t.Fatalf("lookupCronJob() error = %v", err)
+	}
+	if got.Timezone != "Europe/London" {
+		t.Errorf("lookupCronJob().Timezone = %s, want Europe/London", got.Timezone)
+	}
+}
+
+func TestRequestsHandler_parseRegionTimezonesInvalid(t *testing.T) {
+	for _, entry := range []string{"eu-west-2", "=Europe/London", "eu-west-2=", "eu-west-2=auto"} {
+		h := &RequestsHandler{RegionTimezones: []string{entry}}
+		if err := h.parseRegionTimezones(); err == nil {
+			t.Errorf("parseRegionTimezones(%q) expected error", entry)
+		}
+	}
+}
+
+func TestRequestsHandler_handleJobAdmissionRequest(t *testing.T) {
+	h := &RequestsHandler{TimezoneRules: []string{"group:emea=Europe/Berlin"}}
+	if err := h.parseTimezoneRules(); err != nil {
+		t.Fatalf("parseTimezoneRules() error = %v", err)
+	}
+
+	request := &admission.AdmissionRequest{
+		Namespace: "default",
+		UserInfo:  authenticationv1.UserInfo{Username: "bob", Groups: []string{"emea"}},
+		Object:    runtime.RawExtension{Raw: []byte(`{"apiVersion":"batch/v1","kind":"Job","metadata":{"name":"report"},"spec":{"template":{"spec":{"containers":[{"name":"report","image":"busybox"}]}}}}`)},
+	}
+
+	response := &admission.AdmissionResponse{}
+	patches, err := h.handleJobAdmissionRequest(request, response)
+	if err != nil {
+		t.Fatalf("handleJobAdmissionRequest() error = %v", err)
+	}
+
+	want := k8tz.Patches{
+		{Op: "add", Path: "/spec/template/metadata/annotations", Value: map[string]string{}},
+		{Op: "add", Path: "/spec/template/metadata/annotations/k8tz.io~1timezone-rule", Value: "group:emea=Europe/Berlin"},
+	}
+	if fmt.Sprint(patches) != fmt.Sprint(want) {
+		t.Errorf("handleJobAdmissionRequest() = %v, want %v", patches, want)
+	}
+	if got := response.AuditAnnotations[TimezoneRuleAuditAnnotation]; got != "group:emea=Europe/Berlin" {
+		t.Errorf("audit annotation = %q, want %q", got, "group:emea=Europe/Berlin")
+	}
+
+	request.UserInfo = authenticationv1.UserInfo{Username: "carol"}
+	patches, err = h.handleJobAdmissionRequest(request, &admission.AdmissionResponse{})
+	if err != nil || len(patches) != 0 {
+		t.Errorf("handleJobAdmissionRequest() without matching rule = %v, %v, want no patches", patches, err)
+	}
+
+	request.UserInfo = authenticationv1.UserInfo{Username: "bob", Groups: []string{"emea"}}
+	for _, raw := range []string{
+		`{"apiVersion":"batch/v1","kind":"Job","metadata":{"name":"report","labels":{"k8tz.io/timezone":"Asia_Tokyo"}},"spec":{"template":{"spec":{"containers":[{"name":"report","image":"busybox"}]}}}}`,
+		`{"apiVersion":"batch/v1","kind":"Job","metadata":{"name":"report"},"spec":{"template":{"metadata":{"labels":{"k8tz.io/timezone":"Asia_Tokyo"}},"spec":{"containers":[{"name":"report","image":"busybox"}]}}}}`,
+	} {
+		request.Object = runtime.RawExtension{Raw: []byte(raw)}
+		patches, err = h.handleJobAdmissionRequest(request, &admission.AdmissionResponse{})
+		if err != nil || len(patches) != 0 {
+			t.Errorf("handleJobAdmissionRequest() with timezone label = %v, %v, want no patches", patches, err)
+		}
+	}
+}
+
+func TestRequestsHandler_podTimezoneRule(t *testing.T) {
+	k8tz.WarningLogger.SetOutput(io.Discard)
+
+	h := &RequestsHandler{
+		DefaultTimezone:          k8tz.UTCTimezone,
+		DefaultInjectionStrategy: inject.InitContainerInjectionStrategy,
+		InjectByDefault:          true,
+		TimezoneRules:            []string{"user:alice=Asia/Tokyo", "group:emea=Europe/Berlin"},
+		clientset:                fake.NewSimpleClientset(testNamespace(map[string]string{k8tz.TimezoneAnnotation: "Europe/Namespace"})),
+	}
+	if err := h.parseTimezoneRules(); err != nil {
+		t.Fatalf("parseTimezoneRules() error = %v", err)
+	}
+	jobController := authenticationv1.UserInfo{Username: "system:serviceaccount:kube-system:job-controller"}
+	job := testOwnerReference("batch/v1", "Job", "report")
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		owners      []v1.OwnerReference
+		userInfo    authenticationv1.UserInfo
+		want        string
+	}{
+		{
+			name:        "rule recorded by the job wins over the namespace",
+			annotations: map[string]string{k8tz.TimezoneRuleAnnotation: "group:emea=Europe/Berlin"},
+			owners:      []v1.OwnerReference{job},
+			userInfo:    jobController,
+			want:        "Europe/Berlin",
+		},
+		{
+			name:        "recorded rule is ignored on pods without a job",
+			annotations: map[string]string{k8tz.TimezoneRuleAnnotation: "group:emea=Europe/Berlin"},
+			userInfo:    authenticationv1.UserInfo{Username: "bob"},
+			want:        "Europe/Namespace",
+		},
+		{
+			name:        "recorded rule is ignored on pods of other controllers",
+			annotations: map[string]string{k8tz.TimezoneRuleAnnotation: "group:emea=Europe/Berlin"},
+			owners:      []v1.OwnerReference{testOwnerReference("apps/v1", "ReplicaSet", "web")},
+			userInfo:    authenticationv1.UserInfo{Username: "system:serviceaccount:kube-system:replicaset-controller"},
+			want:        "Europe/Namespace",
+		},
+		{
+			name:        "recorded rule that is not configured is ignored",
+			annotations: map[string]string{k8tz.TimezoneRuleAnnotation: "group:apac=Asia/Singapore"},
+			owners:      []v1.OwnerReference{job},
+			userInfo:    jobController,
+			want:        "Europe/Namespace",
+		},
+		{
+			name:        "pod annotation wins over the recorded rule",
+			annotations: map[string]string{k8tz.TimezoneRuleAnnotation: "group:emea=Europe/Berlin", k8tz.TimezoneAnnotation: "Europe/Pod"},
+			owners:      []v1.OwnerReference{job},
+			userInfo:    jobController,
+			want:        "Europe/Pod",
+		},
+		{
+			name:        "matching user wins over the recorded rule",
+			annotations: map[string]string{k8tz.TimezoneRuleAnnotation: "group:emea=Europe/Berlin"},
+			userInfo:    authenticationv1.UserInfo{Username: "alice"},
+			want:        "Asia/Tokyo",
+		},
+		{
+			name:        "invalid recorded rule is ignored",
+			annotations: map[string]string{k8tz.TimezoneRuleAnnotation: "Europe/Berlin"},
+			owners:      []v1.OwnerReference{job},
+			userInfo:    jobController,
+			want:        "Europe/Namespace",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := testPod(tt.annotations, tt.owners...)
+			got, _, err := h.lookupPod("default", pod, h.podTimezoneRule(pod, tt.userInfo))
+			if err != nil {
+				t.Fatalf("lookupPod() error = %v", err)
+			}
+			if got.Timezone != tt.want {
+				t.Errorf("lookupPod().Timezone = %s, want %s", got.Timezone, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestsHandler_enforceAllowedTimezones(t *testing.T) {
+	tests := []struct {
+		name         string
+		annotations  map[string]string
+		timezone     string
+		want         string
+		wantErr      bool
+		wantWarnings int
+		wantAudit    string
+	}{
+		{
+			name:     "no allowed timezones",
+			timezone: "Asia/Tokyo",
+			want:     "Asia/Tokyo",
+		},
+		{
+			name:        "allowed timezone",
+			annotations: map[string]string{k8tz.AllowedTimezonesAnnotation: "UTC, Europe/*"},
+			timezone:    "Europe/London",
+			want:        "Europe/London",
+		},
+		{
+			name:        "disallowed timezone is rejected",
+			annotations: map[string]string{k8tz.AllowedTimezonesAnnotation: "UTC,Europe/*"},
+			timezone:    "Asia/Tokyo",
+			wantErr:     true,
+			wantAudit:   "rejected Asia/Tokyo",
+		},
+		{
+			name:         "disallowed timezone is coerced",
+			annotations:  map[string]string{k8tz.AllowedTimezonesAnnotation: "Europe/*,UTC", k8tz.TimezonePolicyAnnotation: "coerce"},
+			timezone:     "Asia/Tokyo",
+			want:         "UTC",
+			wantWarnings: 1,
+			wantAudit:    "coerced Asia/Tokyo to UTC",
+		},
+		{
+			name:        "coerce without a timezone to coerce to rejects",
+			annotations: map[string]string{k8tz.AllowedTimezonesAnnotation: "Europe/*", k8tz.TimezonePolicyAnnotation: "coerce"},
+			timezone:    "Asia/Tokyo",
+			wantErr:     true,
+			wantAudit:   "rejected Asia/Tokyo",
+		},
+		{
+			name:        "unknown policy rejects",
+			annotations: map[string]string{k8tz.AllowedTimezonesAnnotation: "UTC", k8tz.TimezonePolicyAnnotation: "ignore"},
+			timezone:    "Asia/Tokyo",
+			wantErr:     true,
+			wantAudit:   "rejected Asia/Tokyo",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k8tz.WarningLogger.SetOutput(io.Discard)
+
+			h := &RequestsHandler{}
+			sources := []annotationSource{
+				// tenants cannot loosen the allowed timezones
+				{name: "pod", annotations: map[string]string{k8tz.AllowedTimezonesAnnotation: "*", k8tz.TimezonePolicyAnnotation: "reject"}},
+				h.objectAnnotationSource("namespace", &testNamespace(tt.annotations).ObjectMeta),
+			}
+			generator := &inject.PatchGenerator{Timezone: tt.timezone}
+			response := &admission.AdmissionResponse{}
+			rejected := testutil.ToFloat64(timezonePolicyDecisions.WithLabelValues("default", timezoneRejectedDecision))
+			coerced := testutil.ToFloat64(timezonePolicyDecisions.WithLabelValues("default", timezoneCoercedDecision))
+
+			err := h.enforceAllowedTimezones("default", testPod(nil).ObjectMeta, sources, generator, response)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("enforceAllowedTimezones() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && generator.Timezone != tt.want {
+				t.Errorf("enforceAllowedTimezones() timezone = %s, want %s", generator.Timezone, tt.want)
+			}
+			if len(response.Warnings) != tt.wantWarnings {
+				t.Errorf("enforceAllowedTimezones() warnings = %v, want %d", response.Warnings, tt.wantWarnings)
+			}
+			if got := response.AuditAnnotations[TimezonePolicyAuditAnnotation]; got != tt.wantAudit {
+				t.Errorf("audit annotation = %q, want %q", got, tt.wantAudit)
+			}
+			rejected = testutil.ToFloat64(timezonePolicyDecisions.WithLabelValues("default", timezoneRejectedDecision)) - rejected
+			coerced = testutil.ToFloat64(timezonePolicyDecisions.WithLabelValues("default", timezoneCoercedDecision)) - coerced
+			if want := boolToFloat(strings.HasPrefix(tt.wantAudit, timezoneRejectedDecision)); rejected != want {
+				t.Errorf("rejected decisions = %v, want %v", rejected, want)
+			}
+			if want := boolToFloat(strings.HasPrefix(tt.wantAudit, timezoneCoercedDecision)); coerced != want {
+				t.Errorf("coerced decisions = %v, want %v", coerced, want)
+			}
+		})
+	}
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func TestRequestsHandler_handlePodAdmissionRequestAllowedTimezones(t *testing.T) {
+	h := &RequestsHandler{
+		DefaultTimezone:          k8tz.UTCTimezone,
+		DefaultInjectionStrategy: inject.InitContainerInjectionStrategy,
+		InjectByDefault:          true,
+		clientset:                fake.NewSimpleClientset(testNamespace(map[string]string{k8tz.AllowedTimezonesAnnotation: "UTC"})),
+	}
+
+	request := &admission.AdmissionRequest{
+		Namespace: "default",
+		Object:    runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"app","annotations":{"k8tz.io/timezone":"Asia/Tokyo"}},"spec":{"containers":[{"name":"app","image":"busybox"}]}}`)},
+	}
+
+	_, err := h.handlePodAdmissionRequest(request, &admission.AdmissionResponse{})
+	if err == nil || !strings.Contains(err.Error(), "timezone Asia/Tokyo is not allowed in namespace default") {
+		t.Errorf("handlePodAdmissionRequest() error = %v, want timezone not allowed", err)
+	}
+}
+
+func TestRequestsHandler_handleCronJobAdmissionRequestPodTemplate(t *testing.T) {
+	tests := []struct {
+		name               string
+		cronJobTimeZone    bool
+		cronJobPodTemplate bool
+		namespaceLabels    map[string]string
+		wantPaths          []string
+		wantWarning        bool
+	}{
+		{
+			name:      "nothing is injected by default",
+			wantPaths: nil,
+		},
+		{
+			name:            "timeZone only",
+			cronJobTimeZone: true,
+			wantPaths:       []string{"/spec/timeZone"},
+		},
+		{
+			name:               "pod template only",
+			cronJobPodTemplate: true,
+			wantPaths:          []string{"/spec/jobTemplate/spec/template/metadata", "/spec/jobTemplate/spec/template/spec/volumes"},
+		},
+		{
+			name:               "pod template and timeZone",
+			cronJobTimeZone:    true,
+			cronJobPodTemplate: true,
+			wantPaths:          []string{"/spec/jobTemplate/spec/template/metadata", "/spec/jobTemplate/spec/template/spec/volumes", "/spec/timeZone"},
+		},
+		{
+			name:               "pod template violating pod security is skipped with a warning",
+			cronJobTimeZone:    true,
+			cronJobPodTemplate: true,
+			namespaceLabels:    map[string]string{"pod-security.kubernetes.io/enforce": "restricted"},
+			wantPaths:          []string{"/spec/timeZone"},
+			wantWarning:        true,
+		},
+		{
+			name:               "pod template only violating pod security",
+			cronJobPodTemplate: true,
+			namespaceLabels:    map[string]string{"pod-security.kubernetes.io/enforce": "baseline"},
+			wantPaths:          nil,
+			wantWarning:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			namespace := testNamespace(nil)
+			namespace.Labels = tt.namespaceLabels
+			h := &RequestsHandler{
+				DefaultTimezone:          k8tz.UTCTimezone,
+				DefaultInjectionStrategy: inject.HostPathInjectionStrategy,
+				InjectByDefault:          true,
+				HostPathPrefix:           inject.DefaultHostPathPrefix,
+				LocalTimePath:            inject.DefaultLocalTimePath,
+				VolumeName:               inject.DefaultVolumeName,
+				CronJobTimeZone:          tt.cronJobTimeZone,
+				CronJobPodTemplate:       tt.cronJobPodTemplate,
+				clientset:                fake.NewSimpleClientset(namespace),
+			}
+
+			request := &admission.AdmissionRequest{
+				Namespace: "default",
+				Object:    runtime.RawExtension{Raw: []byte(`{"apiVersion":"batch/v1","kind":"CronJob","metadata":{"name":"hello","annotations":{"k8tz.io/timezone":"Europe/Dublin"}},"spec":{"schedule":"* * * * *","jobTemplate":{"spec":{"template":{"spec":{"containers":[{"name":"hello","image":"busybox"}]}}}}}}`)},
+			}
+
+			response := &admission.AdmissionResponse{}
+			patches, err := h.handleCronJobAdmissionRequest(request, response)
+			if err != nil {
+				t.Fatalf("handleCronJobAdmissionRequest() error = %v", err)
+			}
+			if got := len(response.Warnings) > 0; got != tt.wantWarning {
+				t.Errorf("handleCronJobAdmissionRequest() warnings = %v, want warning %t", response.Warnings, tt.wantWarning)
+			}
+
+			paths := map[string]bool{}
+			for _, p := range patches {
+				paths[p.Path] = true
+			}
+			for _, path := range tt.wantPaths {
+				if !paths[path] {
+					t.Errorf("handleCronJobAdmissionRequest() patches = %+v, missing %s", patches, path)
+				}
+			}
+			for _, p := range patches {
+				if tt.wantWarning && strings.HasPrefix(p.Path, "/spec/jobTemplate") {
+					t.Errorf("handleCronJobAdmissionRequest() patched the pod template with %+v", p)
+				}
+			}
+			if tt.wantPaths == nil && len(patches) != 0 {
+				t.Errorf("handleCronJobAdmissionRequest() patches = %+v, want none", patches)
+			}
+		})
+	}
+}
+
+func TestRequestsHandler_handleCronJobAdmissionRequestScheduleRewrite(t *testing.T) {
+	h := &RequestsHandler{
+		DefaultTimezone:          "Asia/Kolkata",
+		DefaultInjectionStrategy: inject.HostPathInjectionStrategy,
+		InjectByDefault:          true,
+		CronJobScheduleRewrite:   true,
+		clientset:                fake.NewSimpleClientset(testNamespace(nil)),
+	}
+
+	request := &admission.AdmissionRequest{
+		Namespace: "default",
+		Object:    runtime.RawExtension{Raw: []byte(`{"apiVersion":"batch/v1","kind":"CronJob","metadata":{"name":"hello"},"spec":{"schedule":"0,45 9 * * *","jobTemplate":{"spec":{"template":{"spec":{"containers":[{"name":"hello","image":"busybox"}]}}}}}}`)},
+	}
+
+	response := &admission.AdmissionResponse{}
+	patches, err := h.handleCronJobAdmissionRequest(request, response)
+	if err != nil {
+		t.Fatalf("handleCronJobAdmissionRequest() error = %v", err)
+	}
+
+	schedules := map[string]interface{}{}
+	for _, p := range patches {
+		schedules[p.Path] = p.Value
+	}
+	if got := schedules["/spec/schedule"]; got != "0,45 4 * * *" {
+		t.Errorf("rewritten schedule = %v, want 0,45 4 * * *", got)
+	}
+	if got := schedules["/metadata/annotations/k8tz.io~1original-schedule"]; got != "0,45 9 * * *" {
+		t.Errorf("original schedule annotation = %v, want 0,45 9 * * *", got)
+	}
+	if len(response.Warnings) != 1 {
+		t.Errorf("handleCronJobAdmissionRequest() warnings = %v, want 1", response.Warnings)
+	}
+}
+
+func TestRequestsHandler_parseDefaultsConfigMap(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    map[string]string
+		want    handlerDefaults
+		wantErr bool
+	}{
+		{
+			name: "missing keys keep flag defaults",
+			data: map[string]string{},
+			want: handlerDefaults{Timezone: k8tz.UTCTimezone, InjectionStrategy: inject.InitContainerInjectionStrategy, InjectByDefault: true, Source: configMapDefaultsSource},
+		},
+		{
+			name: "all keys",
+			data: map[string]string{DefaultsTimezoneKey: "Europe/London", DefaultsInjectionStrategyKey: "hostPath", DefaultsInjectKey: "false"},
+			want: handlerDefaults{Timezone: "Europe/London", InjectionStrategy: inject.HostPathInjectionStrategy, InjectByDefault: false, Source: configMapDefaultsSource},
+		},
+		{
+			name:    "empty timezone",
+			data:    map[string]string{DefaultsTimezoneKey: ""},
+			wantErr: true,
+		},
+		{
+			name:    "invalid inject",
+			data:    map[string]string{DefaultsInjectKey: "sometimes"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid injection strategy",
+			data:    map[string]string{DefaultsInjectionStrategyKey: "foo"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid timezone",
+			data:    map[string]string{DefaultsTimezoneKey: "Europe/Nowhere"},
+			wantErr: true,
+		},
+		{
+			name: "auto timezone and strategy",
+			data: map[string]string{DefaultsTimezoneKey: k8tz.AutoTimezone, DefaultsInjectionStrategyKey: "auto"},
+			want: handlerDefaults{Timezone: k8tz.AutoTimezone, InjectionStrategy: inject.AutoInjectionStrategy, InjectByDefault: true, Source: configMapDefaultsSource},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &RequestsHandler{
+				DefaultTimezone:          k8tz.UTCTimezone,
+				DefaultInjectionStrategy: inject.InitContainerInjectionStrategy,
+				InjectByDefault:          true,
+			}
+
+			got, err := h.parseDefaultsConfigMap(&corev1.ConfigMap{Data: tt.data})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseDefaultsConfigMap() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("parseDefaultsConfigMap() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestsHandler_startDefaultsWatcher(t *testing.T) {
+	k8tz.InfoLogger.SetOutput(io.Discard)
+	k8tz.WarningLogger.SetOutput(io.Discard)
+	k8tz.ErrorLogger.SetOutput(io.Discard)
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: v1.ObjectMeta{Name: "k8tz-defaults", Namespace: "k8tz"},
+		Data:       map[string]string{DefaultsTimezoneKey: "Europe/London"},
+	}
+	clientset := fake.NewSimpleClientset(testNamespace(nil), configMap)
+	h := &RequestsHandler{
+		DefaultTimezone:          k8tz.UTCTimezone,
+		DefaultInjectionStrategy: inject.InitContainerInjectionStrategy,
+		InjectByDefault:          true,
+		DefaultsConfigMap:        "k8tz/k8tz-defaults",
+		clientset:                clientset,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := h.startDefaultsWatcher(ctx); err != nil {
+		t.Fatalf("startDefaultsWatcher() error = %v", err)
+	}
+
+	got, _, err := h.lookupPod("default", testPod(nil), nil)
+	if err != nil {
+		t.Fatalf("lookupPod() error = %v", err)
+	}
+	if got.Timezone != "Europe/London" {
+		t.Errorf("lookupPod().Timezone = %s, want Europe/London", got.Timezone)
+	}
+
+	waitForDefaults := func(want handlerDefaults) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for h.currentDefaults() != want {
+			if time.Now().After(deadline) {
+				t.Fatalf("currentDefaults() = %s, want %s", h.currentDefaults(), want)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	configMap.Data = map[string]string{DefaultsTimezoneKey: "Asia/Tokyo", DefaultsInjectKey: "false"}
+	if _, err := clientset.CoreV1().ConfigMaps("k8tz").Update(context.TODO(), configMap, v1.UpdateOptions{}); err != nil {
+		t.Fatalf("failed to update configmap: %v", err)
+	}
+	waitForDefaults(handlerDefaults{Timezone: "Asia/Tokyo", InjectionStrategy: inject.InitContainerInjectionStrategy, InjectByDefault: false, Source: configMapDefaultsSource})
+
+	for _, data := range []map[string]string{
+		{DefaultsInjectKey: "maybe"},
+		{DefaultsInjectionStrategyKey: "foo"},
+		{DefaultsTimezoneKey: "Europe/Nowhere"},
+	} {
+		configMap.Data = data
+		if _, err := clientset.CoreV1().ConfigMaps("k8tz").Update(context.TODO(), configMap, v1.UpdateOptions{}); err != nil {
+			t.Fatalf("failed to update configmap: %v", err)
+		}
+		time.Sleep(100 * time.Millisecond)
+		waitForDefaults(handlerDefaults{Timezone: "Asia/Tokyo", InjectionStrategy: inject.InitContainerInjectionStrategy, InjectByDefault: false, Source: configMapDefaultsSource})
+	}
+
+	if err := clientset.CoreV1().ConfigMaps("k8tz").Delete(context.TODO(), "k8tz-defaults", v1.DeleteOptions{}); err != nil {
+		t.Fatalf("failed to delete configmap: %v", err)
+	}
+	waitForDefaults(h.staticDefaults())
+}
+
+func TestRequestsHandler_explain(t *testing.T) {
+	h := &RequestsHandler{
+		DefaultTimezone:          k8tz.UTCTimezone,
+		DefaultInjectionStrategy: inject.InitContainerInjectionStrategy,
+		InjectByDefault:          true,
+		defaults:                 &atomic.Pointer[handlerDefaults]{},
+	}
+	h.defaults.Store(&handlerDefaults{Timezone: "Asia/Tokyo", InjectionStrategy: inject.HostPathInjectionStrategy, InjectByDefault: false, Source: configMapDefaultsSource})
+
+	recorder := httptest.NewRecorder()
+	h.explain(recorder, httptest.NewRequest(http.MethodGet, "/explain", nil))
+
+	want := `{"timezone":"Asia/Tokyo","injectionStrategy":"hostPath","inject":false,"source":"configmap"}` + "\n"
+	if got := recorder.Body.String(); got != want {
+		t.Errorf("explain() = %s, want %s", got, want)
+	}
+
+	expected := `
+		# HELP k8tz_defaults_info The active defaults of the webhook, from its flags or the defaults ConfigMap
+		# TYPE k8tz_defaults_info gauge
+		k8tz_defaults_info{inject="false",injection_strategy="hostPath",source="configmap",timezone="Asia/Tokyo"} 1
+	`
+	if err := testutil.CollectAndCompare(&defaultsCollector{handler: h}, strings.NewReader(expected)); err != nil {
+		t.Errorf("defaultsCollector: %v", err)
+	}
+}
+
 func testNamespace(annotations map[string]string) *corev1.Namespace {
 	return &corev1.Namespace{
 		ObjectMeta: v1.ObjectMeta{
@@ -0,0 +1,130 @@
+/*
+Copyright © 2026 Yonatan Kahana
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	k8tz "github.com/k8tz/k8tz/pkg"
+	"github.com/k8tz/k8tz/pkg/inject"
+	admission "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// This file pins the timezones of a namespace to an allowed list, for
+// namespaces that must run in UTC or a fixed set of timezones. Only namespace
+// annotations are honored, so tenants cannot loosen the list from their
+// workloads.
+
+// TimezonePolicyAuditAnnotation is the audit annotation that reports a
+// timezone that was rejected or coerced by the allowed timezones of the
+// namespace
+const TimezonePolicyAuditAnnotation = "timezone-policy"
+
+const (
+	// rejectTimezonePolicy rejects objects whose timezone is not allowed
+	rejectTimezonePolicy = "reject"
+	// coerceTimezonePolicy replaces a timezone that is not allowed with the
+	// first allowed timezone that is not a pattern
+	coerceTimezonePolicy = "coerce"
+)
+
+// parseAllowedTimezones splits the comma separated allowed timezones
+func parseAllowedTimezones(value string) []string {
+	var allowed []string
+	for _, timezone := range strings.Split(value, ",") {
+		if timezone = strings.TrimSpace(timezone); timezone != "" {
+			allowed = append(allowed, timezone)
+		}
+	}
+
+	return allowed
+}
+
+func isTimezoneAllowed(allowed []string, timezone string) bool {
+	for _, pattern := range allowed {
+		if matched, _ := path.Match(pattern, timezone); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// coercedTimezone returns the first allowed timezone that is not a pattern
+func coercedTimezone(allowed []string) (string, bool) {
+	for _, timezone := range allowed {
+		if !strings.ContainsAny(timezone, `*?[\`) {
+			return timezone, true
+		}
+	}
+
+	return "", false
+}
+
+// enforceAllowedTimezones checks the timezone of the generator against the
+// allowed timezones of the namespace and its ancestors. A timezone that is not
+// allowed fails the request, or is replaced with an allowed one when the
+// namespace policy is coerce. Either decision is reported as a warning, as an
+// audit annotation and in the timezone policy decisions metric. Only the
+// namespace sources among the annotation sources of the object, as resolved
+// by its lookup, are honored.
+func (h *RequestsHandler) enforceAllowedTimezones(namespace string, objectMeta metav1.ObjectMeta, annotationSources []annotationSource, generator *inject.PatchGenerator, response *admission.AdmissionResponse) error {
+	sources := namespaceAnnotationSources(annotationSources)
+	val, source, ok := lookupAnnotation(sources, k8tz.AllowedTimezonesAnnotation)
+	if !ok {
+		return nil
+	}
+
+	allowed := parseAllowedTimezones(val)
+	if isTimezoneAllowed(allowed, generator.Timezone) {
+		return nil
+	}
+
+	policy, _, _ := lookupAnnotation(sources, k8tz.TimezonePolicyAnnotation)
+	switch policy {
+	case "", rejectTimezonePolicy:
+	case coerceTimezonePolicy:
+		if timezone, ok := coercedTimezone(allowed); ok {
+			message := fmt.Sprintf("timezone %s is not allowed in namespace %s, %s is used instead", generator.Timezone, namespace, timezone)
+			k8tz.WarningLogger.Printf("coercing timezone of object (%s): %s", formatObjectDetails(objectMeta), message)
+			setTimezonePolicyAuditAnnotation(response, fmt.Sprintf("coerced %s to %s", generator.Timezone, timezone))
+			timezonePolicyDecisions.WithLabelValues(namespace, timezoneCoercedDecision).Inc()
+			response.Warnings = append(response.Warnings, "k8tz: "+message)
+			generator.Timezone = timezone
+			return nil
+		}
+
+		k8tz.WarningLogger.Printf("allowed timezones of %s (%s) has no timezone to coerce to, rejecting instead", source, val)
+	default:
+		k8tz.WarningLogger.Printf("unknown timezone policy %q on %s, rejecting instead", policy, source)
+	}
+
+	setTimezonePolicyAuditAnnotation(response, fmt.Sprintf("rejected %s", generator.Timezone))
+	timezonePolicyDecisions.WithLabelValues(namespace, timezoneRejectedDecision).Inc()
+	return fmt.Errorf("timezone %s is not allowed in namespace %s, allowed timezones are %s", generator.Timezone, namespace, strings.Join(allowed, ", "))
+}
+
+func setTimezonePolicyAuditAnnotation(response *admission.AdmissionResponse, decision string) {
+	if response.AuditAnnotations == nil {
+		response.AuditAnnotations = map[string]string{}
+	}
+
+	response.AuditAnnotations[TimezonePolicyAuditAnnotation] = decision
+}
@@ -18,16 +18,48 @@ package admission
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	k8tz "github.com/k8tz/k8tz/pkg"
+	admission "k8s.io/api/admission/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // This file resolves k8tz annotations for pod admission. Stable lookup uses
 // Pod -> Namespace. Beta owner lookup, when enabled, uses
-// Pod -> controller owner chain -> Namespace. Owner lookup is best-effort so
-// parent API errors do not block pod admission.
+// Pod -> controller owner chain -> Namespace. The pod's ServiceAccount can be
+// added before or after the owners, and ancestor namespaces after the
+// namespace. Owner and ServiceAccount lookups are best-effort so parent API
+// errors do not block pod admission.
+
+// ServiceAccountLookup decides whether, and where in the precedence chain, the
+// annotations of the pod's ServiceAccount are consulted
+type ServiceAccountLookup string
+
+const (
+	// DisabledServiceAccountLookup does not consult the ServiceAccount
+	DisabledServiceAccountLookup ServiceAccountLookup = "disabled"
+	// BeforeOwnersServiceAccountLookup consults the ServiceAccount right
+	// after the pod, before its controller owners
+	BeforeOwnersServiceAccountLookup ServiceAccountLookup = "beforeOwners"
+	// AfterOwnersServiceAccountLookup consults the ServiceAccount after the
+	// controller owners, right before the namespace
+	AfterOwnersServiceAccountLookup ServiceAccountLookup = "afterOwners"
+)
+
+// AnnotationSourcesAuditAnnotation is the audit annotation that reports the
+// source of the effective timezone, strategy and inject values of a pod, e.g.
+// "timezone=serviceaccount,strategy=namespace,inject=default"
+const AnnotationSourcesAuditAnnotation = "annotation-sources"
+
+const (
+	// timezoneRuleSource is the source of a timezone from a timezone rule
+	timezoneRuleSource = "rule"
+	// defaultSource is the source of the values from the webhook defaults
+	defaultSource = "default"
+)
 
 // maxOwnerAnnotationDepth prevents unexpected owner-reference loops from
 // causing unbounded API lookups.
@@ -51,29 +83,98 @@ func lookupAnnotation(sources []annotationSource, annotation string) (string, st
 	return "", "", false
 }
 
+// lookupTimezone returns the timezone of the annotation sources and its
+// source. The timezone of the rule that matched the requesting user, if any,
+// comes after the pod and owner annotations and before the namespace
+// annotations.
+func lookupTimezone(sources []annotationSource, rule *timezoneRule) (string, string, bool) {
+	if val, source, ok := lookupAnnotation(sources, k8tz.TimezoneAnnotation); ok && (rule == nil || !isNamespaceSource(source)) {
+		return val, source, true
+	} else if rule != nil {
+		return rule.Timezone, timezoneRuleSource, true
+	}
+
+	return "", "", false
+}
+
+// setAnnotationSourcesAuditAnnotation reports the source that supplied each
+// effective value of the pod, or "default" for the webhook defaults
+func setAnnotationSourcesAuditAnnotation(response *admission.AdmissionResponse, sources []annotationSource, rule *timezoneRule) {
+	sourceName := func(source string, ok bool) string {
+		if !ok {
+			return defaultSource
+		}
+		return strings.ToLower(source)
+	}
+
+	_, timezoneSource, timezoneOk := lookupTimezone(sources, rule)
+	_, strategySource, strategyOk := lookupAnnotation(sources, k8tz.InjectionStrategyAnnotation)
+	_, injectSource, injectOk := lookupAnnotation(sources, k8tz.InjectAnnotation)
+
+	if response.AuditAnnotations == nil {
+		response.AuditAnnotations = map[string]string{}
+	}
+
+	response.AuditAnnotations[AnnotationSourcesAuditAnnotation] = fmt.Sprintf("timezone=%s,strategy=%s,inject=%s",
+		sourceName(timezoneSource, timezoneOk), sourceName(strategySource, strategyOk), sourceName(injectSource, injectOk))
+}
+
+// isNamespaceSource reports whether the annotation source is a namespace or
+// one of its ancestors, whose annotations are managed by cluster
+// administrators rather than tenants
+func isNamespaceSource(name string) bool {
+	return name == "namespace" || name == "parentNamespace"
+}
+
 // lookupPodAnnotationSources builds the annotation source list for a pod,
 // preserving the precedence expected by lookupAnnotation. Owner sources are
 // included only when the beta pod owner lookup feature is enabled.
 func (h *RequestsHandler) lookupPodAnnotationSources(namespace string, pod *corev1.Pod, namespaceObj *corev1.Namespace, includeOwners bool) []annotationSource {
-	sources := []annotationSource{
-		{
-			name:        "pod",
-			annotations: pod.Annotations,
-		},
+	sources := []annotationSource{h.objectAnnotationSource("pod", &pod.ObjectMeta)}
+
+	if h.ServiceAccountLookup == BeforeOwnersServiceAccountLookup {
+		sources = append(sources, h.lookupServiceAccountAnnotationSources(namespace, pod)...)
 	}
 
 	if includeOwners {
 		sources = append(sources, h.lookupOwnerAnnotationSources(namespace, &pod.ObjectMeta, 0)...)
 	}
 
-	sources = append(sources, annotationSource{
-		name:        "namespace",
-		annotations: namespaceObj.Annotations,
-	})
+	if h.ServiceAccountLookup == AfterOwnersServiceAccountLookup {
+		sources = append(sources, h.lookupServiceAccountAnnotationSources(namespace, pod)...)
+	}
+
+	sources = append(sources, h.lookupNamespaceAnnotationSources(namespaceObj)...)
 
 	return sources
 }
 
+func (h *RequestsHandler) checkServiceAccountLookup() error {
+	switch h.ServiceAccountLookup {
+	case "", DisabledServiceAccountLookup, BeforeOwnersServiceAccountLookup, AfterOwnersServiceAccountLookup:
+		return nil
+	}
+
+	return fmt.Errorf("unknown service account lookup specified: %s", h.ServiceAccountLookup)
+}
+
+// lookupServiceAccountAnnotationSources returns the annotations of the pod's
+// ServiceAccount. Lookup errors are logged and treated as a missing source.
+func (h *RequestsHandler) lookupServiceAccountAnnotationSources(namespace string, pod *corev1.Pod) []annotationSource {
+	name := pod.Spec.ServiceAccountName
+	if name == "" {
+		name = "default"
+	}
+
+	serviceAccount, err := h.clientset.CoreV1().ServiceAccounts(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		k8tz.WarningLogger.Printf("failed to lookup pod ServiceAccount namespace=%s, name=%s: %v", namespace, name, err)
+		return nil
+	}
+
+	return []annotationSource{h.objectAnnotationSource("serviceAccount", &serviceAccount.ObjectMeta)}
+}
+
 // lookupOwnerAnnotationSources follows only the controller owner reference for
 // the object and ignores non-controller owners.
 func (h *RequestsHandler) lookupOwnerAnnotationSources(namespace string, objectMeta *metav1.ObjectMeta, depth int) []annotationSource {
@@ -106,7 +207,7 @@ func (h *RequestsHandler) lookupOwnerReferenceAnnotationSources(namespace string
 			return nil
 		}
 
-		sources := []annotationSource{{name: "replicaSet", annotations: replicaSet.Annotations}}
+		sources := []annotationSource{h.objectAnnotationSource("replicaSet", &replicaSet.ObjectMeta)}
 		return append(sources, h.lookupOwnerAnnotationSources(namespace, &replicaSet.ObjectMeta, depth+1)...)
 
 	case ownerRef.APIVersion == "apps/v1" && ownerRef.Kind == "Deployment":
@@ -116,7 +217,7 @@ func (h *RequestsHandler) lookupOwnerReferenceAnnotationSources(namespace string
 			return nil
 		}
 
-		sources := []annotationSource{{name: "deployment", annotations: deployment.Annotations}}
+		sources := []annotationSource{h.objectAnnotationSource("deployment", &deployment.ObjectMeta)}
 		return append(sources, h.lookupOwnerAnnotationSources(namespace, &deployment.ObjectMeta, depth+1)...)
 
 	case ownerRef.APIVersion == "apps/v1" && ownerRef.Kind == "StatefulSet":
@@ -126,7 +227,7 @@ func (h *RequestsHandler) lookupOwnerReferenceAnnotationSources(namespace string
 			return nil
 		}
 
-		sources := []annotationSource{{name: "statefulSet", annotations: statefulSet.Annotations}}
+		sources := []annotationSource{h.objectAnnotationSource("statefulSet", &statefulSet.ObjectMeta)}
 		return append(sources, h.lookupOwnerAnnotationSources(namespace, &statefulSet.ObjectMeta, depth+1)...)
 
 	case ownerRef.APIVersion == "apps/v1" && ownerRef.Kind == "DaemonSet":
@@ -136,7 +237,7 @@ func (h *RequestsHandler) lookupOwnerReferenceAnnotationSources(namespace string
 			return nil
 		}
 
-		sources := []annotationSource{{name: "daemonSet", annotations: daemonSet.Annotations}}
+		sources := []annotationSource{h.objectAnnotationSource("daemonSet", &daemonSet.ObjectMeta)}
 		return append(sources, h.lookupOwnerAnnotationSources(namespace, &daemonSet.ObjectMeta, depth+1)...)
 
 	case ownerRef.APIVersion == "batch/v1" && ownerRef.Kind == "Job":
@@ -146,7 +247,7 @@ func (h *RequestsHandler) lookupOwnerReferenceAnnotationSources(namespace string
 			return nil
 		}
 
-		sources := []annotationSource{{name: "job", annotations: job.Annotations}}
+		sources := []annotationSource{h.objectAnnotationSource("job", &job.ObjectMeta)}
 		return append(sources, h.lookupOwnerAnnotationSources(namespace, &job.ObjectMeta, depth+1)...)
 
 	case ownerRef.APIVersion == "batch/v1" && ownerRef.Kind == "CronJob":
@@ -156,7 +257,7 @@ func (h *RequestsHandler) lookupOwnerReferenceAnnotationSources(namespace string
 			return nil
 		}
 
-		sources := []annotationSource{{name: "cronJob", annotations: cronJob.Annotations}}
+		sources := []annotationSource{h.objectAnnotationSource("cronJob", &cronJob.ObjectMeta)}
 		return append(sources, h.lookupOwnerAnnotationSources(namespace, &cronJob.ObjectMeta, depth+1)...)
 	}
 
@@ -0,0 +1,254 @@
+/*
+Copyright © 2026 Yonatan Kahana
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	k8tz "github.com/k8tz/k8tz/pkg"
+	"github.com/k8tz/k8tz/pkg/inject"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+)
+
+// This file watches an optional ConfigMap whose keys replace the default
+// timezone, injection strategy and inject-by-default settings of the webhook
+// at runtime, e.g. to flip the default timezone on a failover without
+// restarting the webhook. Keys that are missing from the ConfigMap keep the
+// values of the webhook flags. The active defaults are served on /explain and
+// reported by the k8tz_defaults_info metric.
+
+const (
+	// DefaultsTimezoneKey is the ConfigMap key of the default timezone
+	DefaultsTimezoneKey = "timezone"
+	// DefaultsInjectionStrategyKey is the ConfigMap key of the default
+	// injection strategy
+	DefaultsInjectionStrategyKey = "injectionStrategy"
+	// DefaultsInjectKey is the ConfigMap key of whether pods are injected
+	// by default, "true" or "false"
+	DefaultsInjectKey = "inject"
+)
+
+const (
+	flagsDefaultsSource     = "flags"
+	configMapDefaultsSource = "configmap"
+)
+
+// handlerDefaults are the settings that apply to objects without annotations,
+// they are always replaced together so a request never sees a mix of old and
+// new values
+type handlerDefaults struct {
+	Timezone          string                   `json:"timezone"`
+	InjectionStrategy inject.InjectionStrategy `json:"injectionStrategy"`
+	InjectByDefault   bool                     `json:"inject"`
+	// Source is where the defaults come from, the webhook flags or the
+	// ConfigMap
+	Source string `json:"source"`
+}
+
+func (d handlerDefaults) String() string {
+	return fmt.Sprintf("timezone=%s, injectionStrategy=%s, inject=%t, source=%s", d.Timezone, d.InjectionStrategy, d.InjectByDefault, d.Source)
+}
+
+// currentDefaults returns the defaults from the watched ConfigMap, if any,
+// or the defaults from the webhook flags
+func (h *RequestsHandler) currentDefaults() handlerDefaults {
+	if h.defaults != nil {
+		if defaults := h.defaults.Load(); defaults != nil {
+			return *defaults
+		}
+	}
+
+	return h.staticDefaults()
+}
+
+func (h *RequestsHandler) staticDefaults() handlerDefaults {
+	return handlerDefaults{
+		Timezone:          h.DefaultTimezone,
+		InjectionStrategy: h.DefaultInjectionStrategy,
+		InjectByDefault:   h.InjectByDefault,
+		Source:            flagsDefaultsSource,
+	}
+}
+
+// parseDefaultsConfigMapRef splits DefaultsConfigMap into namespace and name
+func (h *RequestsHandler) parseDefaultsConfigMapRef() (string, string, error) {
+	namespace, name, ok := strings.Cut(h.DefaultsConfigMap, "/")
+	if !ok || namespace == "" || name == "" || strings.Contains(name, "/") {
+		return "", "", fmt.Errorf("invalid defaults configmap %q, expected <namespace>/<name>", h.DefaultsConfigMap)
+	}
+
+	return namespace, name, nil
+}
+
+// parseDefaultsConfigMap returns the defaults of the webhook flags with the
+// values of the ConfigMap applied on top of them
+func (h *RequestsHandler) parseDefaultsConfigMap(configMap *corev1.ConfigMap) (handlerDefaults, error) {
+	defaults := h.staticDefaults()
+	defaults.Source = configMapDefaultsSource
+
+	if val, ok := configMap.Data[DefaultsTimezoneKey]; ok {
+		if err := checkDefaultTimezone(val); err != nil {
+			return handlerDefaults{}, err
+		}
+		defaults.Timezone = val
+	}
+
+	if val, ok := configMap.Data[DefaultsInjectionStrategyKey]; ok {
+		if err := checkDefaultInjectionStrategy(inject.InjectionStrategy(val)); err != nil {
+			return handlerDefaults{}, err
+		}
+		defaults.InjectionStrategy = inject.InjectionStrategy(val)
+	}
+
+	if val, ok := configMap.Data[DefaultsInjectKey]; ok {
+		inject, err := strconv.ParseBool(val)
+		if err != nil {
+			return handlerDefaults{}, fmt.Errorf("invalid %s value %q: %v", DefaultsInjectKey, val, err)
+		}
+		defaults.InjectByDefault = inject
+	}
+
+	return defaults, nil
+}
+
+// checkDefaultTimezone refuses timezones that would fail the injection of
+// every pod without an explicit timezone
+func checkDefaultTimezone(timezone string) error {
+	switch timezone {
+	case "":
+		return fmt.Errorf("%s must not be empty", DefaultsTimezoneKey)
+	case k8tz.AutoTimezone, k8tz.HostTimezone:
+		return nil
+	}
+
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return fmt.Errorf("invalid %s value %q: %v", DefaultsTimezoneKey, timezone, err)
+	}
+
+	return nil
+}
+
+// checkDefaultInjectionStrategy refuses strategies that would fail the
+// injection of every pod without an explicit strategy
+func checkDefaultInjectionStrategy(strategy inject.InjectionStrategy) error {
+	switch strategy {
+	case inject.InitContainerInjectionStrategy, inject.HostPathInjectionStrategy, inject.ImageVolumeInjectionStrategy,
+		inject.ConfigMapInjectionStrategy, inject.EnvInjectionStrategy, inject.AutoInjectionStrategy:
+		return nil
+	}
+
+	return fmt.Errorf("invalid %s value %q, expected initContainer, hostPath, imageVolume, configMap, env or auto", DefaultsInjectionStrategyKey, strategy)
+}
+
+// applyDefaultsConfigMap replaces the current defaults with the values of
+// the ConfigMap, or with the defaults of the webhook flags when the ConfigMap
+// is nil. Invalid ConfigMaps are logged and keep the current defaults.
+func (h *RequestsHandler) applyDefaultsConfigMap(configMap *corev1.ConfigMap) {
+	defaults := h.staticDefaults()
+	if configMap != nil {
+		var err error
+		defaults, err = h.parseDefaultsConfigMap(configMap)
+		if err != nil {
+			k8tz.ErrorLogger.Printf("ignoring invalid defaults configmap %s: %v, current defaults are %s", h.DefaultsConfigMap, err, h.currentDefaults())
+			return
+		}
+	}
+
+	h.defaults.Store(&defaults)
+	k8tz.InfoLogger.Printf("applied defaults from configmap %s: %s", h.DefaultsConfigMap, defaults)
+}
+
+// startDefaultsWatcher applies the defaults ConfigMap and keeps watching it
+// until ctx is done. It returns once the initial state of the ConfigMap was
+// applied, so the first requests already use its values.
+func (h *RequestsHandler) startDefaultsWatcher(ctx context.Context) error {
+	namespace, name, err := h.parseDefaultsConfigMapRef()
+	if err != nil {
+		return err
+	}
+
+	h.defaults = &atomic.Pointer[handlerDefaults]{}
+
+	factory := informers.NewSharedInformerFactoryWithOptions(
+		h.clientset, 0,
+		informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+		}),
+	)
+	configMapInformer := factory.Core().V1().ConfigMaps().Informer()
+
+	go factory.Start(ctx.Done())
+
+	if !cache.WaitForCacheSync(ctx.Done(), configMapInformer.HasSynced) {
+		return fmt.Errorf("timed out waiting for defaults configMapInformer caches to sync")
+	}
+
+	configMap, err := factory.Core().V1().ConfigMaps().Lister().ConfigMaps(namespace).Get(name)
+	if err != nil {
+		k8tz.WarningLogger.Printf("defaults configmap %s was not found, using the defaults of the webhook flags", h.DefaultsConfigMap)
+		configMap = nil
+	}
+	h.applyDefaultsConfigMap(configMap)
+
+	_, err = configMapInformer.AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: func(obj interface{}) bool {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			configMap, ok := obj.(*corev1.ConfigMap)
+			return ok && configMap.Name == name
+		},
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				h.applyDefaultsConfigMap(obj.(*corev1.ConfigMap))
+			},
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				h.applyDefaultsConfigMap(newObj.(*corev1.ConfigMap))
+			},
+			DeleteFunc: func(obj interface{}) {
+				k8tz.WarningLogger.Printf("defaults configmap %s was deleted, using the defaults of the webhook flags", h.DefaultsConfigMap)
+				h.applyDefaultsConfigMap(nil)
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to register EventHandler for defaults configMapInformer")
+	}
+
+	return nil
+}
+
+// explain serves the active defaults as JSON, so it is possible to check
+// which values the webhook applies after the ConfigMap was changed
+func (h *RequestsHandler) explain(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", jsonContentType)
+	if err := json.NewEncoder(w).Encode(h.currentDefaults()); err != nil {
+		k8tz.ErrorLogger.Printf("failed to write explain response: %v", err)
+	}
+}
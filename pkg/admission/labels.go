@@ -0,0 +1,104 @@
+/*
+Copyright © 2026 Yonatan Kahana
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"fmt"
+	"strings"
+
+	k8tz "github.com/k8tz/k8tz/pkg"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// This file lets labels stand in for annotations. Labels can be set by tools
+// that cannot set annotations, and can be matched by webhook selectors. Only
+// the keys in labelAnnotations are read from labels, and label values are
+// decoded because characters such as "/" are not valid in them.
+
+// MetadataPrecedence decides whether the label or the annotation wins when the
+// same object has both for the same key
+type MetadataPrecedence string
+
+const (
+	// AnnotationsMetadataPrecedence prefers annotations over labels
+	AnnotationsMetadataPrecedence MetadataPrecedence = "annotations"
+	// LabelsMetadataPrecedence prefers labels over annotations
+	LabelsMetadataPrecedence MetadataPrecedence = "labels"
+)
+
+// labelAnnotations are the annotations that may also be set as labels
+var labelAnnotations = []string{
+	k8tz.TimezoneAnnotation,
+	k8tz.InjectAnnotation,
+	k8tz.InjectionStrategyAnnotation,
+}
+
+// timezoneLabelReplacer decodes timezone label values, where "_" stands for
+// "/" and "__" for a literal "_", e.g. "America_New__York" is
+// "America/New_York"
+var timezoneLabelReplacer = strings.NewReplacer("__", "_", "_", "/")
+
+func (h *RequestsHandler) checkMetadataPrecedence() error {
+	switch h.MetadataPrecedence {
+	case "", AnnotationsMetadataPrecedence, LabelsMetadataPrecedence:
+		return nil
+	}
+
+	return fmt.Errorf("unknown metadata precedence specified: %s", h.MetadataPrecedence)
+}
+
+// decodeLabelValue converts a label value to the equivalent annotation value
+func decodeLabelValue(annotation string, value string) string {
+	if annotation == k8tz.TimezoneAnnotation {
+		return timezoneLabelReplacer.Replace(value)
+	}
+
+	return value
+}
+
+// objectAnnotations returns the annotations of the object merged with the
+// decoded values of its labelAnnotations labels, by the configured precedence
+func (h *RequestsHandler) objectAnnotations(objectMeta *metav1.ObjectMeta) map[string]string {
+	annotations := make(map[string]string, len(objectMeta.Annotations))
+	for key, val := range objectMeta.Annotations {
+		annotations[key] = val
+	}
+
+	for _, key := range labelAnnotations {
+		val, ok := objectMeta.Labels[key]
+		if !ok {
+			continue
+		}
+
+		if _, exists := annotations[key]; exists && h.MetadataPrecedence != LabelsMetadataPrecedence {
+			continue
+		}
+
+		annotations[key] = decodeLabelValue(key, val)
+	}
+
+	return annotations
+}
+
+// objectAnnotationSource returns the object as a source in the annotation
+// precedence chain, including its labels
+func (h *RequestsHandler) objectAnnotationSource(name string, objectMeta *metav1.ObjectMeta) annotationSource {
+	return annotationSource{
+		name:        name,
+		annotations: h.objectAnnotations(objectMeta),
+	}
+}
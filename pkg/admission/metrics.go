@@ -0,0 +1,74 @@
+/*
+Copyright © 2026 Yonatan Kahana
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// This file holds the metrics of the webhook, which are served on /metrics
+// in the Prometheus exposition format.
+
+const (
+	timezoneRejectedDecision = "rejected"
+	timezoneCoercedDecision  = "coerced"
+)
+
+var (
+	metricsRegistry = prometheus.NewRegistry()
+
+	timezonePolicyDecisions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "k8tz",
+		Name:      "timezone_policy_decisions_total",
+		Help:      "Number of timezones that were not allowed in their namespace, by decision (rejected/coerced)",
+	}, []string{"namespace", "decision"})
+)
+
+var defaultsInfoDesc = prometheus.NewDesc(
+	"k8tz_defaults_info",
+	"The active defaults of the webhook, from its flags or the defaults ConfigMap",
+	[]string{"timezone", "injection_strategy", "inject", "source"}, nil,
+)
+
+// defaultsCollector reports the defaults of the handler as they are at
+// scrape time, so the metric follows the changes of the defaults ConfigMap
+type defaultsCollector struct {
+	handler *RequestsHandler
+}
+
+func (c *defaultsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- defaultsInfoDesc
+}
+
+func (c *defaultsCollector) Collect(ch chan<- prometheus.Metric) {
+	defaults := c.handler.currentDefaults()
+	ch <- prometheus.MustNewConstMetric(defaultsInfoDesc, prometheus.GaugeValue, 1,
+		defaults.Timezone, string(defaults.InjectionStrategy), strconv.FormatBool(defaults.InjectByDefault), defaults.Source)
+}
+
+func init() {
+	metricsRegistry.MustRegister(timezonePolicyDecisions)
+}
+
+// metricsHandler serves the metrics of the webhook
+func metricsHandler() http.Handler {
+	return promhttp.HandlerFor(metricsRegistry, promhttp.HandlerOpts{})
+}
@@ -0,0 +1,170 @@
+/*
+Copyright © 2026 Yonatan Kahana
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	k8tz "github.com/k8tz/k8tz/pkg"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// This file resolves the ancestors of a namespace, for multi-tenancy tools
+// that nest namespaces, so child namespaces inherit the k8tz annotations of
+// their parents. Like owner lookups, ancestor lookups are best-effort.
+
+// NamespaceHierarchy decides how the ancestors of a namespace are found
+type NamespaceHierarchy string
+
+const (
+	// DisabledNamespaceHierarchy consults only the namespace of the pod
+	DisabledNamespaceHierarchy NamespaceHierarchy = "disabled"
+	// HNCNamespaceHierarchy finds the ancestors by the depth labels that the
+	// Hierarchical Namespace Controller sets on every namespace
+	HNCNamespaceHierarchy NamespaceHierarchy = "hnc"
+	// LabelNamespaceHierarchy follows a label whose value is the name of the
+	// parent namespace, see RequestsHandler.NamespaceParentLabel
+	LabelNamespaceHierarchy NamespaceHierarchy = "label"
+
+	// hncDepthLabelSuffix is the suffix of the "<ancestor>.tree.hnc.x-k8s.io/depth"
+	// labels, whose value is the distance from the labeled namespace
+	hncDepthLabelSuffix = ".tree.hnc.x-k8s.io/depth"
+)
+
+// maxNamespaceAncestorDepth bounds the number of ancestor namespaces that
+// are looked up, and prevents parent label loops from causing unbounded API
+// lookups.
+const maxNamespaceAncestorDepth = 8
+
+func (h *RequestsHandler) checkNamespaceHierarchy() error {
+	switch h.NamespaceHierarchy {
+	case "", DisabledNamespaceHierarchy, HNCNamespaceHierarchy:
+		return nil
+	case LabelNamespaceHierarchy:
+		if h.NamespaceParentLabel == "" {
+			return fmt.Errorf("namespace parent label must be specified with %s namespace hierarchy", LabelNamespaceHierarchy)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("unknown namespace hierarchy specified: %s", h.NamespaceHierarchy)
+}
+
+// lookupNamespaceAnnotationSources returns the namespace followed by its
+// ancestors, from the closest to the farthest
+func (h *RequestsHandler) lookupNamespaceAnnotationSources(namespaceObj *corev1.Namespace) []annotationSource {
+	sources := []annotationSource{h.objectAnnotationSource("namespace", &namespaceObj.ObjectMeta)}
+
+	var ancestors []string
+	switch h.NamespaceHierarchy {
+	case HNCNamespaceHierarchy:
+		ancestors = hncAncestors(namespaceObj)
+	case LabelNamespaceHierarchy:
+		return append(sources, h.lookupParentNamespaceAnnotationSources(namespaceObj)...)
+	}
+
+	for _, name := range ancestors {
+		parent, err := h.clientset.CoreV1().Namespaces().Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			k8tz.WarningLogger.Printf("failed to lookup ancestor namespace %s of namespace %s: %v", name, namespaceObj.Name, err)
+			continue
+		}
+
+		sources = append(sources, h.objectAnnotationSource("parentNamespace", &parent.ObjectMeta))
+	}
+
+	return sources
+}
+
+// lookupParentNamespaceAnnotationSources follows NamespaceParentLabel from the
+// namespace up to maxNamespaceAncestorDepth ancestors
+func (h *RequestsHandler) lookupParentNamespaceAnnotationSources(namespaceObj *corev1.Namespace) []annotationSource {
+	var sources []annotationSource
+	visited := map[string]bool{namespaceObj.Name: true}
+
+	for current := namespaceObj; ; {
+		name, ok := current.Labels[h.NamespaceParentLabel]
+		if !ok || name == "" {
+			return sources
+		}
+
+		if visited[name] {
+			k8tz.WarningLogger.Printf("stopping ancestor lookup of namespace %s because of a parent label loop at namespace %s", namespaceObj.Name, name)
+			return sources
+		}
+
+		if len(sources) >= maxNamespaceAncestorDepth {
+			k8tz.WarningLogger.Printf("stopping ancestor lookup of namespace %s after %d ancestors", namespaceObj.Name, maxNamespaceAncestorDepth)
+			return sources
+		}
+
+		parent, err := h.clientset.CoreV1().Namespaces().Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			k8tz.WarningLogger.Printf("failed to lookup parent namespace %s of namespace %s: %v", name, current.Name, err)
+			return sources
+		}
+
+		visited[name] = true
+		sources = append(sources, h.objectAnnotationSource("parentNamespace", &parent.ObjectMeta))
+		current = parent
+	}
+}
+
+// hncAncestors returns the names of the ancestors of the namespace from its
+// HNC depth labels, ordered from the parent up to maxNamespaceAncestorDepth
+func hncAncestors(namespaceObj *corev1.Namespace) []string {
+	depths := map[string]int{}
+	var ancestors []string
+	for key, val := range namespaceObj.Labels {
+		name := strings.TrimSuffix(key, hncDepthLabelSuffix)
+		if name == key {
+			continue
+		}
+
+		depth, err := strconv.Atoi(val)
+		if err != nil || depth < 1 || depth > maxNamespaceAncestorDepth {
+			continue
+		}
+
+		depths[name] = depth
+		ancestors = append(ancestors, name)
+	}
+
+	sort.Slice(ancestors, func(i, j int) bool {
+		return depths[ancestors[i]] < depths[ancestors[j]]
+	})
+
+	return ancestors
+}
+
+// mergeAnnotationSources flattens sources into a single annotations map
+// where the closest source wins for every key
+func mergeAnnotationSources(sources []annotationSource) map[string]string {
+	merged := map[string]string{}
+	for i := len(sources) - 1; i >= 0; i-- {
+		for key, val := range sources[i].annotations {
+			merged[key] = val
+		}
+	}
+
+	return merged
+}
@@ -0,0 +1,174 @@
+/*
+Copyright © 2026 Yonatan Kahana
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	k8tz "github.com/k8tz/k8tz/pkg"
+	"github.com/k8tz/k8tz/pkg/inject"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// This file applies override annotations, which replace the webhook settings
+// of a single pod. Overrides on the namespace are always honored since only
+// cluster administrators are expected to annotate namespaces, overrides on
+// the pod and its owners, or on the cronJob, are honored only when listed in
+// AllowedOverrides.
+
+// AllOverrides allows tenants to use every override annotation
+const AllOverrides = "*"
+
+// DefaultAllowedOverrides are the overrides that workloads may use unless the
+// webhook flags say otherwise, since they were workload annotations before
+// overrides had to be allowed
+var DefaultAllowedOverrides = []string{"zoneinfo-path", "runtime-profile", "tzdata-image"}
+
+// override binds an override annotation to the generator field it sets
+type override struct {
+	annotation string
+	apply      func(generator *inject.PatchGenerator, value string) error
+}
+
+var overrides = []override{
+	{annotation: k8tz.ContainerNameAnnotation, apply: func(g *inject.PatchGenerator, v string) error {
+		g.InitContainerName = v
+		return nil
+	}},
+	{annotation: k8tz.BootstrapImageAnnotation, apply: func(g *inject.PatchGenerator, v string) error {
+		g.InitContainerImage = v
+		return nil
+	}},
+	{annotation: k8tz.BootstrapResourcesAnnotation, apply: func(g *inject.PatchGenerator, v string) error {
+		g.InitContainerResources = v
+		return nil
+	}},
+	{annotation: k8tz.BootstrapVerboseAnnotation, apply: func(g *inject.PatchGenerator, v string) (err error) {
+		g.InitContainerVerbose, err = strconv.ParseBool(v)
+		return err
+	}},
+	{annotation: k8tz.HostPathPrefixAnnotation, apply: func(g *inject.PatchGenerator, v string) error {
+		g.HostPathPrefix = v
+		return nil
+	}},
+	{annotation: k8tz.LocalTimePathAnnotation, apply: func(g *inject.PatchGenerator, v string) error {
+		g.LocalTimePath = v
+		return nil
+	}},
+	{annotation: k8tz.TimezoneFilePathAnnotation, apply: func(g *inject.PatchGenerator, v string) error {
+		g.TimezoneFilePath = v
+		return nil
+	}},
+	{annotation: k8tz.VolumeNameAnnotation, apply: func(g *inject.PatchGenerator, v string) error {
+		g.VolumeName = v
+		return nil
+	}},
+	{annotation: k8tz.MountConflictPolicyAnnotation, apply: func(g *inject.PatchGenerator, v string) error {
+		g.MountConflictPolicy = inject.MountConflictPolicy(v)
+		return nil
+	}},
+	{annotation: k8tz.ImagePullPolicyAnnotation, apply: func(g *inject.PatchGenerator, v string) error {
+		g.ImagePullPolicy = corev1.PullPolicy(v)
+		return nil
+	}},
+	{annotation: k8tz.ImagePullSecretsAnnotation, apply: func(g *inject.PatchGenerator, v string) error {
+		g.ImagePullSecrets = nil
+		for _, secret := range strings.Split(v, ",") {
+			if secret = strings.TrimSpace(secret); secret != "" {
+				g.ImagePullSecrets = append(g.ImagePullSecrets, secret)
+			}
+		}
+		return nil
+	}},
+	{annotation: k8tz.EmptyDirSizeLimitAnnotation, apply: func(g *inject.PatchGenerator, v string) error {
+		g.EmptyDirSizeLimit = v
+		return nil
+	}},
+	{annotation: k8tz.EmptyDirMediumAnnotation, apply: func(g *inject.PatchGenerator, v string) error {
+		g.EmptyDirMedium = corev1.StorageMedium(v)
+		return nil
+	}},
+	{annotation: k8tz.ZoneinfoPathAnnotation, apply: func(g *inject.PatchGenerator, v string) error {
+		g.ZoneinfoMountPath = v
+		return nil
+	}},
+	{annotation: k8tz.RuntimeProfileAnnotation, apply: func(g *inject.PatchGenerator, v string) error {
+		g.RuntimeProfile = inject.RuntimeProfile(v)
+		return nil
+	}},
+	{annotation: k8tz.TzdataImageAnnotation, apply: func(g *inject.PatchGenerator, v string) error {
+		g.TzdataImage = v
+		return nil
+	}},
+}
+
+// isOverrideAllowed reports whether tenants may use the override annotation,
+// AllowedOverrides lists annotations without the k8tz.io/ prefix
+func (h *RequestsHandler) isOverrideAllowed(annotation string) bool {
+	name := strings.TrimPrefix(annotation, "k8tz.io/")
+	for _, allowed := range h.AllowedOverrides {
+		if allowed == AllOverrides || allowed == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// applyOverrides sets the generator fields of the override annotations found
+// on the sources of the object, a pod or a cronJob, ignoring tenant
+// annotations that are not allowed
+func (h *RequestsHandler) applyOverrides(generator *inject.PatchGenerator, sources []annotationSource, kind string, objectMeta metav1.ObjectMeta) error {
+	for _, o := range overrides {
+		candidates := sources
+		if !h.isOverrideAllowed(o.annotation) {
+			candidates = namespaceAnnotationSources(sources)
+			if _, source, ok := lookupAnnotation(sources, o.annotation); ok && !isNamespaceSource(source) {
+				k8tz.WarningLogger.Printf("ignoring %s annotation on %s of %s (%s) because the override is not allowed", o.annotation, source, kind, formatObjectDetails(objectMeta))
+			}
+		}
+
+		v, source, ok := lookupAnnotation(candidates, o.annotation)
+		if !ok {
+			continue
+		}
+
+		if err := o.apply(generator, v); err != nil {
+			return fmt.Errorf("invalid %s annotation on %s: %w", o.annotation, source, err)
+		}
+
+		k8tz.InfoLogger.Printf("explicit %s override requested on %s annotation for %s (%s): %s", o.annotation, source, kind, formatObjectDetails(objectMeta), v)
+	}
+
+	return nil
+}
+
+// namespaceAnnotationSources returns only the namespace sources, whose
+// annotations are managed by cluster administrators
+func namespaceAnnotationSources(sources []annotationSource) []annotationSource {
+	var filtered []annotationSource
+	for _, source := range sources {
+		if isNamespaceSource(source.name) {
+			filtered = append(filtered, source)
+		}
+	}
+
+	return filtered
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package admission
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
 	k8tz "github.com/k8tz/k8tz/pkg"
@@ -37,9 +38,12 @@ const (
 var (
 	k8sdecode       = serializer.NewCodecFactory(runtime.NewScheme()).UniversalDeserializer()
 	podResource     = metav1.GroupVersionResource{Version: "v1", Resource: "pods"}
+	jobResource     = metav1.GroupVersionResource{Version: "v1", Resource: "jobs", Group: "batch"}
 	cronJobResource = metav1.GroupVersionResource{Version: "v1", Resource: "cronjobs", Group: "batch"}
 )
 
+const ephemeralContainersSubResource = "ephemeralcontainers"
+
 type Server struct {
 	TLSCertFile     string
 	TLSKeyFile      string
@@ -80,16 +84,68 @@ func (h *Server) Start(kubeconfigFlag string) error {
 		return err
 	}
 
+	if err = h.Handler.parseTimezoneRules(); err != nil {
+		return err
+	}
+
+	if err = h.Handler.parseRegionTimezones(); err != nil {
+		return err
+	}
+
+	if err = h.Handler.checkServiceAccountLookup(); err != nil {
+		return err
+	}
+
+	if err = h.Handler.checkMetadataPrecedence(); err != nil {
+		return err
+	}
+
+	if err = h.Handler.checkNamespaceHierarchy(); err != nil {
+		return err
+	}
+
 	if err = h.Handler.InitializeClientset(kubeconfigFlag); err != nil {
 		return fmt.Errorf("failed to setup connection with kubernetes api: %w", err)
 	}
 
+	if h.Handler.DefaultsConfigMap != "" {
+		if err = h.Handler.startDefaultsWatcher(context.Background()); err != nil {
+			return fmt.Errorf("failed to watch defaults configmap: %w", err)
+		}
+	}
+
+	if h.Handler.configMaps != nil {
+		go func() {
+			if err := h.Handler.configMaps.Start(context.Background()); err != nil {
+				k8tz.ErrorLogger.Printf("configmap controller stopped: %v", err)
+			}
+		}()
+	}
+
+	if h.Handler.cronSchedules != nil {
+		go func() {
+			identity, err := os.Hostname()
+			if err != nil {
+				k8tz.ErrorLogger.Printf("cronjob schedule controller not started: %v", err)
+				return
+			}
+
+			if err := h.Handler.cronSchedules.StartWithLeaderElection(context.Background(), h.Handler.LeaderElectionNamespace, identity); err != nil {
+				k8tz.ErrorLogger.Printf("cronjob schedule controller stopped: %v", err)
+			}
+		}()
+	}
+
+	metricsRegistry.MustRegister(&defaultsCollector{handler: &h.Handler})
+
 	k8tz.InfoLogger.Printf("Listening on %s\n", h.Address)
 
 	mux := http.NewServeMux()
 
 	mux.HandleFunc("/", h.Handler.handleFunc)
 	mux.HandleFunc("/health", h.health)
+	mux.HandleFunc("/explain", h.Handler.explain)
+	mux.Handle("/metrics", metricsHandler())
 
 	server := &http.Server{
 		Addr:    h.Address,
@@ -0,0 +1,82 @@
+/*
+Copyright © 2026 Yonatan Kahana
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"fmt"
+
+	k8tz "github.com/k8tz/k8tz/pkg"
+	"github.com/k8tz/k8tz/pkg/inject"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// This file resolves the auto injection strategy. The strategies in
+// AutoStrategies are tried in order and the first one the cluster and the
+// pod's namespace can run is used.
+
+// DefaultAutoStrategies is the default fallback chain of the auto injection
+// strategy
+var DefaultAutoStrategies = []string{
+	string(inject.ImageVolumeInjectionStrategy),
+	string(inject.InitContainerInjectionStrategy),
+}
+
+// resolveAutoStrategy returns the first strategy of AutoStrategies that is
+// supported for pods in the namespace
+func (h *RequestsHandler) resolveAutoStrategy(namespaceObj *corev1.Namespace) (inject.InjectionStrategy, error) {
+	for _, s := range h.AutoStrategies {
+		strategy := inject.InjectionStrategy(s)
+		supported, reason := h.isStrategySupported(strategy, namespaceObj)
+		if supported {
+			return strategy, nil
+		}
+
+		k8tz.VerboseLogger.Printf("auto strategy: skipping %s for namespace %s, %s", strategy, namespaceObj.Name, reason)
+	}
+
+	return "", fmt.Errorf("none of the auto injection strategies %v is supported in namespace %s", h.AutoStrategies, namespaceObj.Name)
+}
+
+func (h *RequestsHandler) isStrategySupported(strategy inject.InjectionStrategy, namespaceObj *corev1.Namespace) (bool, string) {
+	switch strategy {
+	case inject.ImageVolumeInjectionStrategy:
+		// without subPath support only the zoneinfo directory is mounted,
+		// which is not enough for a complete injection
+		if !h.imageVolumeLocalTime {
+			return false, "image volume subPath is not supported by the kubernetes server"
+		}
+	case inject.HostPathInjectionStrategy:
+		if level := podSecurityLevel(namespaceObj); !level.AllowsHostPath() {
+			return false, fmt.Sprintf("hostPath volumes are not allowed by the %s pod security level", level)
+		}
+	case inject.ConfigMapInjectionStrategy:
+		if h.configMaps == nil {
+			return false, "the configmap controller is disabled"
+		}
+	case inject.InitContainerInjectionStrategy, inject.EnvInjectionStrategy:
+	default:
+		return false, "unknown strategy"
+	}
+
+	return true, ""
+}
+
+// podSecurityLevel returns the Pod Security Standards level enforced on the
+// namespace, or an empty level when it is not labeled
+func podSecurityLevel(namespaceObj *corev1.Namespace) inject.PodSecurityLevel {
+	return inject.PodSecurityLevel(namespaceObj.Labels[inject.PodSecurityEnforceLabel])
+}
@@ -0,0 +1,205 @@
+/*
+Copyright © 2026 Yonatan Kahana
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"fmt"
+	"strings"
+
+	k8tz "github.com/k8tz/k8tz/pkg"
+	"github.com/k8tz/k8tz/pkg/inject"
+	admission "k8s.io/api/admission/v1beta1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// This file maps the user that requests a pod to a timezone, so ad-hoc pods
+// and Jobs of users that share a namespace get their own timezone. Rules are
+// written as <kind>:<name>=<timezone>, where kind is user, group or
+// serviceaccount (<namespace>/<name>), and the first matching rule wins.
+
+// TimezoneRuleAuditAnnotation is the audit annotation that reports the rule
+// that matched the requesting user
+const TimezoneRuleAuditAnnotation = "timezone-rule"
+
+const (
+	userTimezoneRule           = "user"
+	groupTimezoneRule          = "group"
+	serviceAccountTimezoneRule = "serviceaccount"
+)
+
+type timezoneRule struct {
+	Kind     string
+	Name     string
+	Timezone string
+}
+
+func (r *timezoneRule) String() string {
+	return fmt.Sprintf("%s:%s=%s", r.Kind, r.Name, r.Timezone)
+}
+
+func parseTimezoneRule(rule string) (timezoneRule, error) {
+	subject, timezone, ok := strings.Cut(rule, "=")
+	if !ok || timezone == "" {
+		return timezoneRule{}, fmt.Errorf("invalid timezone rule %q, expected <kind>:<name>=<timezone>", rule)
+	}
+
+	kind, name, ok := strings.Cut(subject, ":")
+	if !ok || name == "" {
+		return timezoneRule{}, fmt.Errorf("invalid timezone rule %q, expected <kind>:<name>=<timezone>", rule)
+	}
+
+	switch kind {
+	case userTimezoneRule, groupTimezoneRule:
+	case serviceAccountTimezoneRule:
+		if namespace, account, ok := strings.Cut(name, "/"); !ok || namespace == "" || account == "" {
+			return timezoneRule{}, fmt.Errorf("invalid timezone rule %q, service accounts are written as <namespace>/<name>", rule)
+		}
+	default:
+		return timezoneRule{}, fmt.Errorf("invalid timezone rule %q, unknown kind %s (user/group/serviceaccount)", rule, kind)
+	}
+
+	return timezoneRule{Kind: kind, Name: name, Timezone: timezone}, nil
+}
+
+// parseTimezoneRules validates TimezoneRules before the server starts
+func (h *RequestsHandler) parseTimezoneRules() error {
+	h.timezoneRules = nil
+	for _, r := range h.TimezoneRules {
+		rule, err := parseTimezoneRule(r)
+		if err != nil {
+			return err
+		}
+
+		h.timezoneRules = append(h.timezoneRules, rule)
+	}
+
+	return nil
+}
+
+// matchTimezoneRule returns the first rule that matches the user, or nil
+func (h *RequestsHandler) matchTimezoneRule(userInfo authenticationv1.UserInfo) *timezoneRule {
+	for i := range h.timezoneRules {
+		rule := &h.timezoneRules[i]
+		switch rule.Kind {
+		case userTimezoneRule:
+			if userInfo.Username == rule.Name {
+				return rule
+			}
+		case serviceAccountTimezoneRule:
+			if userInfo.Username == "system:serviceaccount:"+strings.Replace(rule.Name, "/", ":", 1) {
+				return rule
+			}
+		case groupTimezoneRule:
+			for _, group := range userInfo.Groups {
+				if group == rule.Name {
+					return rule
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// podTimezoneRule returns the rule that matches the user creating the pod,
+// or the rule recorded on the pod template of its Job, or nil. The recorded
+// rule is honored only for pods controlled by a Job, and only when it is one
+// of the configured rules, so users cannot pick a rule by annotating pods.
+func (h *RequestsHandler) podTimezoneRule(pod *corev1.Pod, userInfo authenticationv1.UserInfo) *timezoneRule {
+	if rule := h.matchTimezoneRule(userInfo); rule != nil {
+		return rule
+	}
+
+	val, ok := pod.Annotations[k8tz.TimezoneRuleAnnotation]
+	if !ok {
+		return nil
+	}
+
+	if owner := metav1.GetControllerOf(pod); owner == nil || owner.Kind != "Job" {
+		k8tz.WarningLogger.Printf("ignoring %s annotation of pod (%s) that is not controlled by a job", k8tz.TimezoneRuleAnnotation, formatObjectDetails(pod.ObjectMeta))
+		return nil
+	}
+
+	for i := range h.timezoneRules {
+		if rule := &h.timezoneRules[i]; rule.String() == val {
+			return rule
+		}
+	}
+
+	k8tz.WarningLogger.Printf("ignoring %s annotation of pod (%s), %q is not a configured timezone rule", k8tz.TimezoneRuleAnnotation, formatObjectDetails(pod.ObjectMeta), val)
+	return nil
+}
+
+func setTimezoneRuleAuditAnnotation(response *admission.AdmissionResponse, rule *timezoneRule) {
+	if rule == nil {
+		return
+	}
+
+	if response.AuditAnnotations == nil {
+		response.AuditAnnotations = map[string]string{}
+	}
+
+	response.AuditAnnotations[TimezoneRuleAuditAnnotation] = rule.String()
+}
+
+// handleJobAdmissionRequest records the rule that matched the user creating
+// the Job on its pod template, since its pods are created by the Job
+// controller rather than by the user
+func (h *RequestsHandler) handleJobAdmissionRequest(req *admission.AdmissionRequest, response *admission.AdmissionResponse) (k8tz.Patches, error) {
+	rule := h.matchTimezoneRule(req.UserInfo)
+	if rule == nil {
+		return nil, nil
+	}
+
+	job := batchv1.Job{}
+	if _, _, err := k8sdecode.Decode(req.Object.Raw, nil, &job); err != nil {
+		return nil, fmt.Errorf("could not deserialize job object: %v", err)
+	}
+
+	// like pods, an explicit timezone on the Job or its template, in an
+	// annotation or a label, wins over the rule
+	if _, ok := h.objectAnnotations(&job.ObjectMeta)[k8tz.TimezoneAnnotation]; ok {
+		return nil, nil
+	}
+
+	if _, ok := h.objectAnnotations(&job.Spec.Template.ObjectMeta)[k8tz.TimezoneAnnotation]; ok {
+		return nil, nil
+	}
+
+	setTimezoneRuleAuditAnnotation(response, rule)
+	k8tz.InfoLogger.Printf("timezone rule %s matched the requesting user of job (%s)", rule, formatObjectDetails(job.ObjectMeta))
+
+	patches := k8tz.Patches{}
+	if len(job.Spec.Template.Annotations) == 0 {
+		patches = append(patches, k8tz.Patch{
+			Op:    "add",
+			Path:  "/spec/template/metadata/annotations",
+			Value: map[string]string{},
+		})
+	}
+
+	patches = append(patches, k8tz.Patch{
+		Op:    "add",
+		Path:  "/spec/template/metadata/annotations/" + inject.EscapeJsonPointer(k8tz.TimezoneRuleAnnotation),
+		Value: rule.String(),
+	})
+
+	return patches, nil
+}
@@ -0,0 +1,183 @@
+/*
+Copyright © 2026 Yonatan Kahana
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"fmt"
+	"strings"
+
+	k8tz "github.com/k8tz/k8tz/pkg"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// This file derives the timezone of pods with the "auto" timezone from the
+// region or zone they are constrained to. Admission happens before
+// scheduling, so only nodeSelector and required nodeAffinity are considered,
+// and the timezone is derived only when every node the pod may be scheduled
+// on maps to the same timezone.
+
+// parseRegionTimezones validates RegionTimezones, written as
+// <region or zone>=<timezone>, before the server starts
+func (h *RequestsHandler) parseRegionTimezones() error {
+	h.regionTimezones = map[string]string{}
+	for _, entry := range h.RegionTimezones {
+		topology, timezone, ok := strings.Cut(entry, "=")
+		if !ok || topology == "" || timezone == "" || timezone == k8tz.AutoTimezone {
+			return fmt.Errorf("invalid region timezone %q, expected <region or zone>=<timezone>", entry)
+		}
+
+		h.regionTimezones[topology] = timezone
+	}
+
+	return nil
+}
+
+// topologyConstraint is the set of zones and regions that a pod may be
+// scheduled on, nil values are not constrained
+type topologyConstraint struct {
+	zones   []string
+	regions []string
+}
+
+// with returns the constraint narrowed by a node selector requirement.
+// Requirements are ANDed, so repeated requirements on the same key are
+// intersected.
+func (c topologyConstraint) with(requirement corev1.NodeSelectorRequirement) topologyConstraint {
+	if requirement.Operator != corev1.NodeSelectorOpIn {
+		return c
+	}
+
+	switch requirement.Key {
+	case corev1.LabelTopologyZone:
+		c.zones = intersectTopologies(c.zones, requirement.Values)
+	case corev1.LabelTopologyRegion:
+		c.regions = intersectTopologies(c.regions, requirement.Values)
+	}
+
+	return c
+}
+
+// intersectTopologies returns the values that are also in current, or all
+// of them when current is not constrained yet
+func intersectTopologies(current []string, values []string) []string {
+	if current == nil {
+		return append([]string{}, values...)
+	}
+
+	intersection := []string{}
+	for _, val := range values {
+		for _, topology := range current {
+			if val == topology {
+				intersection = append(intersection, val)
+				break
+			}
+		}
+	}
+
+	return intersection
+}
+
+// timezone returns the timezone of the constraint, preferring zones over
+// regions. It fails when a zone or region is not mapped, when they map to
+// different timezones, or when no zone or region is left.
+func (c topologyConstraint) timezone(regionTimezones map[string]string) (string, bool) {
+	topologies := c.zones
+	if topologies == nil {
+		topologies = c.regions
+	}
+
+	timezone := ""
+	for _, topology := range topologies {
+		val, ok := regionTimezones[topology]
+		if !ok || (timezone != "" && val != timezone) {
+			return "", false
+		}
+
+		timezone = val
+	}
+
+	return timezone, timezone != ""
+}
+
+// topologyTimezone derives the timezone from the nodeSelector and the
+// required nodeAffinity of the pod spec. Node selector terms are ORed, so
+// all of them must resolve to the same timezone.
+func (h *RequestsHandler) topologyTimezone(spec *corev1.PodSpec) (string, bool) {
+	base := topologyConstraint{}
+	for _, key := range []string{corev1.LabelTopologyZone, corev1.LabelTopologyRegion} {
+		if val, ok := spec.NodeSelector[key]; ok {
+			base = base.with(corev1.NodeSelectorRequirement{Key: key, Operator: corev1.NodeSelectorOpIn, Values: []string{val}})
+		}
+	}
+
+	constraints := []topologyConstraint{base}
+	if affinity := spec.Affinity; affinity != nil && affinity.NodeAffinity != nil && affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil {
+		constraints = nil
+		for _, term := range affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+			constraint := base
+			for _, requirement := range term.MatchExpressions {
+				constraint = constraint.with(requirement)
+			}
+
+			constraints = append(constraints, constraint)
+		}
+	}
+
+	timezone := ""
+	for _, constraint := range constraints {
+		val, ok := constraint.timezone(h.regionTimezones)
+		if !ok || (timezone != "" && val != timezone) {
+			return "", false
+		}
+
+		timezone = val
+	}
+
+	return timezone, timezone != ""
+}
+
+// withoutAutoTimezone returns the sources without their "auto" timezone
+// annotations, so the timezone can be looked up again when it cannot be
+// derived from the pod's topology
+func withoutAutoTimezone(sources []annotationSource) []annotationSource {
+	filtered := make([]annotationSource, 0, len(sources))
+	for _, source := range sources {
+		if source.annotations[k8tz.TimezoneAnnotation] == k8tz.AutoTimezone {
+			annotations := make(map[string]string, len(source.annotations))
+			for key, val := range source.annotations {
+				if key != k8tz.TimezoneAnnotation {
+					annotations[key] = val
+				}
+			}
+			source.annotations = annotations
+		}
+
+		filtered = append(filtered, source)
+	}
+
+	return filtered
+}
+
+// nonAutoTimezone returns the timezone, or the k8tz default timezone when
+// it is "auto"
+func nonAutoTimezone(timezone string) string {
+	if timezone == k8tz.AutoTimezone {
+		return k8tz.DefaultTimezone
+	}
+
+	return timezone
+}
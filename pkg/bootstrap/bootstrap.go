@@ -8,18 +8,20 @@ import (
 )
 
 type BootstrapOperation struct {
-	From      string
-	To        string
-	Overwrite bool
-	Verbose   bool
+	From          string
+	To            string
+	Overwrite     bool
+	Verbose       bool
+	TimezoneFiles bool
 }
 
 func NewBootstrapOperation() BootstrapOperation {
 	return BootstrapOperation{
-		From:      inject.DefaultHostPathPrefix,
-		To:        "/mnt/zoneinfo",
-		Overwrite: true,
-		Verbose:   false,
+		From:          inject.DefaultHostPathPrefix,
+		To:            "/mnt/zoneinfo",
+		Overwrite:     true,
+		Verbose:       false,
+		TimezoneFiles: false,
 	}
 }
 
@@ -29,5 +31,14 @@ func (o *BootstrapOperation) Bootstrap() error {
 		k8tz.VerboseLogger.Println(version.DisplayVersion())
 		k8tz.VerboseLogger.Printf("bootstrap=%+v", *o)
 	}
-	return copyDirectory(o.From, o.To, o.Overwrite)
+
+	if err := copyDirectory(o.From, o.To, o.Overwrite); err != nil {
+		return err
+	}
+
+	if o.TimezoneFiles {
+		return writeTimezoneFiles(o.To, o.Overwrite)
+	}
+
+	return nil
 }
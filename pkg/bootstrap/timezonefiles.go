@@ -0,0 +1,105 @@
+package bootstrap
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	k8tz "github.com/k8tz/k8tz/pkg"
+	"github.com/k8tz/k8tz/pkg/inject"
+)
+
+var tzifMagic = []byte("TZif")
+
+// writeTimezoneFiles writes a file containing the zone name for every TZif
+// file under root, e.g. root/.timezone/Europe/London contains
+// "Europe/London", so containers can mount it as /etc/timezone
+func writeTimezoneFiles(root string, overwrite bool) error {
+	timezonesDir := filepath.Join(root, inject.TimezoneFilesDir)
+	return filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			if path == timezonesDir {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		isTZif, err := isTZifFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to check file: %s, error: %w", path, err)
+		}
+
+		if !isTZif {
+			return nil
+		}
+
+		zone, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		return writeTimezoneFile(filepath.Join(timezonesDir, zone), filepath.ToSlash(zone), overwrite)
+	})
+}
+
+func writeTimezoneFile(path string, zone string, overwrite bool) error {
+	exists, err := exists(path)
+	if err != nil {
+		return fmt.Errorf("failed to check existence of file: %s, error: %w", path, err)
+	}
+
+	if exists && !overwrite {
+		k8tz.VerboseLogger.Printf("skipping timezone file '%s' because it already exists\n", path)
+		return nil
+	}
+
+	if err := createIfNotExists(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	k8tz.VerboseLogger.Printf("Writing timezone file '%s'\n", path)
+	if err := os.WriteFile(path, []byte(zone+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to write timezone file: %s, error: %w", path, err)
+	}
+
+	return nil
+}
+
+func isTZifFile(path string) (bool, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, err
+	}
+
+	if !info.Mode().IsRegular() {
+		return false, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	header := make([]byte, len(tzifMagic))
+	if _, err := io.ReadFull(file, header); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return bytes.Equal(header, tzifMagic), nil
+}
@@ -0,0 +1,183 @@
+/*
+Copyright © 2026 Yonatan Kahana
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cronschedule
+
+import (
+	"context"
+	"time"
+
+	k8tz "github.com/k8tz/k8tz/pkg"
+
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+const (
+	// DefaultResyncPeriod is the longest time between two reconciliations of
+	// the rewritten CronJobs
+	DefaultResyncPeriod = time.Hour
+
+	// LeaseName is the name of the Lease that elects the single replica that
+	// rewrites the schedules
+	LeaseName = "k8tz-cronjob-schedules"
+)
+
+// Controller keeps the schedules of the CronJobs that were rewritten to UTC
+// on admission, those with the ScheduleRewrittenLabel, in sync with the UTC
+// offset of their timezone. CronJobs are reconciled at each DST transition of
+// their timezones, and at least every ResyncPeriod. A schedule that no longer
+// matches the RewrittenScheduleAnnotation was edited by the user, it replaces
+// the original schedule and is translated as well
+type Controller struct {
+	ResyncPeriod time.Duration
+	clientset    kubernetes.Interface
+}
+
+func NewController(clientset kubernetes.Interface) *Controller {
+	return &Controller{
+		ResyncPeriod: DefaultResyncPeriod,
+		clientset:    clientset,
+	}
+}
+
+// Start reconciles the rewritten CronJobs until ctx is done
+func (c *Controller) Start(ctx context.Context) error {
+	for {
+		now := time.Now()
+		wait := c.ResyncPeriod
+		if next, ok := c.reconcileAll(ctx, now); ok && next.Sub(now) < wait {
+			wait = next.Sub(now)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(wait):
+		}
+	}
+}
+
+// StartWithLeaderElection runs Start only while holding the LeaseName Lease
+// in namespace, so replicas of the webhook do not rewrite the same schedules
+// concurrently. Leadership is campaigned for again after it is lost, until ctx
+// is done
+func (c *Controller) StartWithLeaderElection(ctx context.Context, namespace string, identity string) error {
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta:  metav1.ObjectMeta{Name: LeaseName, Namespace: namespace},
+		Client:     c.clientset.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{Identity: identity},
+	}
+
+	for ctx.Err() == nil {
+		elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+			Lock:            lock,
+			LeaseDuration:   15 * time.Second,
+			RenewDeadline:   10 * time.Second,
+			RetryPeriod:     2 * time.Second,
+			ReleaseOnCancel: true,
+			Name:            LeaseName,
+			Callbacks: leaderelection.LeaderCallbacks{
+				OnStartedLeading: func(ctx context.Context) {
+					k8tz.InfoLogger.Printf("acquired lease %s/%s, rewriting cronjob schedules", namespace, LeaseName)
+					if err := c.Start(ctx); err != nil {
+						k8tz.ErrorLogger.Printf("cronjob schedule controller stopped: %v", err)
+					}
+				},
+				OnStoppedLeading: func() {
+					k8tz.InfoLogger.Printf("lease %s/%s is not held, not rewriting cronjob schedules", namespace, LeaseName)
+				},
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		elector.Run(ctx)
+	}
+
+	return nil
+}
+
+// reconcileAll rewrites the schedules of the rewritten CronJobs for the UTC
+// offsets at now, and returns the next transition of their timezones
+func (c *Controller) reconcileAll(ctx context.Context, now time.Time) (next time.Time, ok bool) {
+	cronJobs, err := c.clientset.BatchV1().CronJobs(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{k8tz.ScheduleRewrittenLabel: "true"}).String(),
+	})
+	if err != nil {
+		k8tz.ErrorLogger.Printf("failed to list cronjobs: %v", err)
+		return next, false
+	}
+
+	for i := range cronJobs.Items {
+		cronJob := &cronJobs.Items[i]
+		if transition, found := c.reconcile(ctx, cronJob, now); found && (!ok || transition.Before(next)) {
+			next, ok = transition, true
+		}
+	}
+
+	return next, ok
+}
+
+// reconcile rewrites the schedule of the CronJob for the UTC offset of its
+// timezone at now, and returns the next transition of the timezone
+func (c *Controller) reconcile(ctx context.Context, cronJob *batchv1.CronJob, now time.Time) (time.Time, bool) {
+	timezone, ok := cronJob.Annotations[k8tz.TimezoneAnnotation]
+	if !ok {
+		k8tz.WarningLogger.Printf("cronjob %s/%s has no %s annotation, skipping", cronJob.Namespace, cronJob.Name, k8tz.TimezoneAnnotation)
+		return time.Time{}, false
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		k8tz.WarningLogger.Printf("cronjob %s/%s has an invalid %s annotation, skipping: %v", cronJob.Namespace, cronJob.Name, k8tz.TimezoneAnnotation, err)
+		return time.Time{}, false
+	}
+
+	original, recorded := cronJob.Annotations[k8tz.OriginalScheduleAnnotation]
+	if rewritten := cronJob.Annotations[k8tz.RewrittenScheduleAnnotation]; !recorded || cronJob.Spec.Schedule != rewritten {
+		k8tz.InfoLogger.Printf("schedule of cronjob %s/%s was changed to %q, using it as the original schedule in %s", cronJob.Namespace, cronJob.Name, cronJob.Spec.Schedule, timezone)
+		original = cronJob.Spec.Schedule
+	}
+
+	schedule, exact, err := Translate(original, loc, now)
+	if err != nil {
+		k8tz.WarningLogger.Printf("cronjob %s/%s has an invalid schedule %q, skipping: %v", cronJob.Namespace, cronJob.Name, original, err)
+		return time.Time{}, false
+	}
+
+	if cronJob.Spec.Schedule != schedule || cronJob.Annotations[k8tz.OriginalScheduleAnnotation] != original || cronJob.Annotations[k8tz.RewrittenScheduleAnnotation] != schedule {
+		if !exact {
+			k8tz.WarningLogger.Printf("schedule %q of cronjob %s/%s cannot be translated exactly from %s to UTC, using %q", original, cronJob.Namespace, cronJob.Name, timezone, schedule)
+		}
+
+		k8tz.InfoLogger.Printf("rewriting schedule of cronjob %s/%s from %q to %q", cronJob.Namespace, cronJob.Name, cronJob.Spec.Schedule, schedule)
+		updated := cronJob.DeepCopy()
+		updated.Spec.Schedule = schedule
+		updated.Annotations[k8tz.OriginalScheduleAnnotation] = original
+		updated.Annotations[k8tz.RewrittenScheduleAnnotation] = schedule
+		if _, err := c.clientset.BatchV1().CronJobs(cronJob.Namespace).Update(ctx, updated, metav1.UpdateOptions{}); err != nil {
+			k8tz.ErrorLogger.Printf("failed to update cronjob %s/%s: %v", cronJob.Namespace, cronJob.Name, err)
+		}
+	}
+
+	return NextTransition(loc, now)
+}
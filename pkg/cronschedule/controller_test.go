@@ -0,0 +1,161 @@
+/*
+Copyright © 2026 Yonatan Kahana
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cronschedule
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	k8tz "github.com/k8tz/k8tz/pkg"
+
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func testCronJob(name string, schedule string, annotations map[string]string) *batchv1.CronJob {
+	var labels map[string]string
+	if _, ok := annotations[k8tz.OriginalScheduleAnnotation]; ok {
+		labels = map[string]string{k8tz.ScheduleRewrittenLabel: "true"}
+	}
+
+	return &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "default",
+			Annotations: annotations,
+			Labels:      labels,
+		},
+		Spec: batchv1.CronJobSpec{
+			Schedule: schedule,
+		},
+	}
+}
+
+func TestController_reconcileAll(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		testCronJob("berlin", "0 7 * * *", map[string]string{
+			k8tz.TimezoneAnnotation:          "Europe/Berlin",
+			k8tz.OriginalScheduleAnnotation:  "0 9 * * *",
+			k8tz.RewrittenScheduleAnnotation: "0 7 * * *",
+		}),
+		testCronJob("new-york", "0 13 * * *", map[string]string{
+			k8tz.TimezoneAnnotation:          "America/New_York",
+			k8tz.OriginalScheduleAnnotation:  "0 9 * * *",
+			k8tz.RewrittenScheduleAnnotation: "0 13 * * *",
+		}),
+		// the user changed the schedule after it was rewritten
+		testCronJob("edited", "30 6 * * *", map[string]string{
+			k8tz.TimezoneAnnotation:          "Europe/Berlin",
+			k8tz.OriginalScheduleAnnotation:  "0 9 * * *",
+			k8tz.RewrittenScheduleAnnotation: "0 7 * * *",
+		}),
+		testCronJob("not-rewritten", "0 9 * * *", map[string]string{
+			k8tz.TimezoneAnnotation: "Europe/Berlin",
+		}),
+	)
+	c := NewController(clientset)
+
+	next, ok := c.reconcileAll(context.Background(), winter)
+	if want := time.Date(2026, time.March, 8, 7, 0, 0, 0, time.UTC); !ok || !next.Equal(want) {
+		t.Errorf("reconcileAll() = %s, %t, want %s", next, ok, want)
+	}
+
+	for name, want := range map[string]struct {
+		schedule string
+		original string
+	}{
+		"berlin":        {schedule: "0 8 * * *", original: "0 9 * * *"},
+		"new-york":      {schedule: "0 14 * * *", original: "0 9 * * *"},
+		"edited":        {schedule: "30 5 * * *", original: "30 6 * * *"},
+		"not-rewritten": {schedule: "0 9 * * *"},
+	} {
+		cronJob, err := clientset.BatchV1().CronJobs("default").Get(context.Background(), name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cronJob.Spec.Schedule != want.schedule {
+			t.Errorf("schedule of cronjob %s = %q, want %q", name, cronJob.Spec.Schedule, want.schedule)
+		}
+		if got := cronJob.Annotations[k8tz.OriginalScheduleAnnotation]; got != want.original {
+			t.Errorf("original schedule of cronjob %s = %q, want %q", name, got, want.original)
+		}
+		if want.original != "" && cronJob.Annotations[k8tz.RewrittenScheduleAnnotation] != want.schedule {
+			t.Errorf("rewritten schedule of cronjob %s = %q, want %q", name, cronJob.Annotations[k8tz.RewrittenScheduleAnnotation], want.schedule)
+		}
+	}
+}
+
+func TestController_reconcileInvalidTimezone(t *testing.T) {
+	cronJob := testCronJob("invalid", "0 9 * * *", map[string]string{
+		k8tz.TimezoneAnnotation:         "Invalid/Zone",
+		k8tz.OriginalScheduleAnnotation: "0 9 * * *",
+	})
+	c := NewController(fake.NewSimpleClientset(cronJob))
+
+	if _, ok := c.reconcile(context.Background(), cronJob, winter); ok {
+		t.Errorf("reconcile() found a transition for an invalid timezone")
+	}
+}
+
+func TestController_StartWithLeaderElection(t *testing.T) {
+	clientset := fake.NewSimpleClientset(testCronJob("utc", "0 9 * * *", map[string]string{
+		k8tz.TimezoneAnnotation:         "UTC",
+		k8tz.OriginalScheduleAnnotation: "0 9 * * *",
+	}))
+	c := NewController(clientset)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- c.StartWithLeaderElection(ctx, "k8tz", "replica-1")
+	}()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		cronJob, err := clientset.BatchV1().CronJobs("default").Get(context.Background(), "utc", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("failed to get cronjob: %v", err)
+		}
+		if cronJob.Annotations[k8tz.RewrittenScheduleAnnotation] == "0 9 * * *" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("cronjob was not reconciled by the leader")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	lease, err := clientset.CoordinationV1().Leases("k8tz").Get(context.Background(), LeaseName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get lease: %v", err)
+	}
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != "replica-1" {
+		t.Errorf("lease holder = %v, want replica-1", lease.Spec.HolderIdentity)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("StartWithLeaderElection() error = %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Errorf("StartWithLeaderElection() did not stop")
+	}
+}
@@ -0,0 +1,377 @@
+/*
+Copyright © 2026 Yonatan Kahana
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cronschedule
+
+import (
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	minutesPerDay  = 24 * 60
+	minutesPerHour = 60
+	// lastCommonDay is the last day of month that every month has, days up
+	// to it can be shifted without moving to another month
+	lastCommonDay = 28
+)
+
+// descriptors are the predefined schedules supported by CronJobs, @every is
+// an interval and does not depend on the timezone
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// field describes the allowed values of a field of a cron expression
+type field struct {
+	name  string
+	min   int
+	max   int
+	names map[string]int
+	// sunday is the value that 7 is an alias of, in the day of week field
+	sunday bool
+}
+
+var (
+	minuteField = field{name: "minute", min: 0, max: 59}
+	hourField   = field{name: "hour", min: 0, max: 23}
+	domField    = field{name: "day of month", min: 1, max: 31}
+	monthField  = field{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	dowField = field{name: "day of week", min: 0, max: 6, sunday: true, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// values is the set of values a field matches, one bit per value
+type values uint64
+
+func (v values) has(value int) bool {
+	return v&(1<<uint(value)) != 0
+}
+
+func (v values) list() []int {
+	var list []int
+	for value := 0; value < 64; value++ {
+		if v.has(value) {
+			list = append(list, value)
+		}
+	}
+
+	return list
+}
+
+func (f field) all() values {
+	var v values
+	for value := f.min; value <= f.max; value++ {
+		v |= 1 << uint(value)
+	}
+
+	return v
+}
+
+// parse parses a field of a cron expression, e.g. "*/15" or "1-5,SAT"
+func (f field) parse(expression string) (values, error) {
+	var v values
+	for _, part := range strings.Split(expression, ",") {
+		r, step, hasStep := strings.Cut(part, "/")
+		increment := 1
+		if hasStep {
+			var err error
+			increment, err = strconv.Atoi(step)
+			if err != nil || increment <= 0 {
+				return 0, fmt.Errorf("invalid step in %s field: %s", f.name, part)
+			}
+		}
+
+		var start, end int
+		if r == "*" || r == "?" {
+			start, end = f.min, f.max
+		} else {
+			low, high, isRange := strings.Cut(r, "-")
+			var err error
+			if start, err = f.value(low); err != nil {
+				return 0, err
+			}
+
+			end = start
+			if isRange {
+				if end, err = f.value(high); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				end = f.max
+			}
+		}
+
+		if start > end {
+			return 0, fmt.Errorf("invalid range in %s field: %s", f.name, part)
+		}
+
+		for value := start; value <= end; value += increment {
+			v |= 1 << uint(value)
+		}
+	}
+
+	if f.sunday && v.has(7) {
+		v = v&^(1<<7) | 1
+	}
+
+	return v, nil
+}
+
+func (f field) value(s string) (int, error) {
+	if value, ok := f.names[strings.ToLower(s)]; ok {
+		return value, nil
+	}
+
+	max := f.max
+	if f.sunday {
+		max = 7
+	}
+
+	value, err := strconv.Atoi(s)
+	if err != nil || value < f.min || value > max {
+		return 0, fmt.Errorf("invalid value in %s field: %s", f.name, s)
+	}
+
+	return value, nil
+}
+
+// format formats the values of the field, keeping the original expression
+// when the values did not change
+func (f field) format(v values, original string, originalValues values) string {
+	if v == originalValues {
+		return original
+	}
+
+	if v == f.all() {
+		return "*"
+	}
+
+	var parts []string
+	list := v.list()
+	for i := 0; i < len(list); {
+		j := i
+		for j+1 < len(list) && list[j+1] == list[j]+1 {
+			j++
+		}
+
+		switch {
+		case j == i:
+			parts = append(parts, strconv.Itoa(list[i]))
+		case j == i+1:
+			parts = append(parts, strconv.Itoa(list[i]), strconv.Itoa(list[j]))
+		default:
+			parts = append(parts, fmt.Sprintf("%d-%d", list[i], list[j]))
+		}
+
+		i = j + 1
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// Translate rewrites the cron schedule, in the local time of loc, to the
+// equivalent schedule in UTC for the UTC offset that loc has at now, i.e.
+// until its next DST transition. The translation is not exact when the
+// shifted occurrences cannot be expressed by a single cron expression, e.g.
+// when some of them move to another day and the days are restricted, or when
+// an offset that is not a whole hour splits the minutes across hours. Then
+// the schedule is translated as closely as possible: days that cannot be
+// shifted are kept, and minutes are kept when they cannot be shifted
+func Translate(schedule string, loc *time.Location, now time.Time) (translated string, exact bool, err error) {
+	schedule = strings.TrimSpace(schedule)
+	if strings.HasPrefix(schedule, "TZ=") || strings.HasPrefix(schedule, "CRON_TZ=") {
+		return "", false, fmt.Errorf("schedule already sets a timezone: %s", schedule)
+	}
+
+	if strings.HasPrefix(schedule, "@every ") {
+		return schedule, true, nil
+	}
+
+	expression := schedule
+	if v, ok := descriptors[strings.ToLower(schedule)]; ok {
+		expression = v
+	}
+
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return "", false, fmt.Errorf("invalid schedule, expected 5 fields: %s", schedule)
+	}
+
+	minutes, err := minuteField.parse(fields[0])
+	if err != nil {
+		return "", false, err
+	}
+
+	hours, err := hourField.parse(fields[1])
+	if err != nil {
+		return "", false, err
+	}
+
+	doms, err := domField.parse(fields[2])
+	if err != nil {
+		return "", false, err
+	}
+
+	months, err := monthField.parse(fields[3])
+	if err != nil {
+		return "", false, err
+	}
+
+	dows, err := dowField.parse(fields[4])
+	if err != nil {
+		return "", false, err
+	}
+
+	_, offset := now.In(loc).Zone()
+	offsetMinutes := offset / 60
+
+	exact = true
+	utcMinutes, utcHours, dayShifts, ok := shift(minutes, hours, offsetMinutes)
+	if !ok {
+		// keep the minutes and shift only the whole hours of the offset
+		exact = false
+		utcMinutes, utcHours, dayShifts, _ = shift(minutes, hours, offsetMinutes-offsetMinutes%minutesPerHour)
+	}
+
+	utcDoms, utcDows := doms, dows
+	dayShift, single := singleDayShift(dayShifts)
+	if !(single && dayShift == 0) && (doms != domField.all() || months != monthField.all() || dows != dowField.all()) {
+		if single && (doms != domField.all() && shiftableDays(doms, dayShift) || doms == domField.all() && months == monthField.all()) {
+			if doms != domField.all() {
+				utcDoms = shiftDays(doms, dayShift)
+			}
+			utcDows = rotateWeekdays(dows, dayShift)
+		} else {
+			exact = false
+		}
+	}
+
+	translated = strings.Join([]string{
+		minuteField.format(utcMinutes, fields[0], minutes),
+		hourField.format(utcHours, fields[1], hours),
+		domField.format(utcDoms, fields[2], doms),
+		monthField.format(months, fields[3], months),
+		dowField.format(utcDows, fields[4], dows),
+	}, " ")
+
+	if translated == expression {
+		// keeps descriptors as is
+		translated = schedule
+	}
+
+	return translated, exact, nil
+}
+
+// shift shifts every combination of minutes and hours by the offset, and
+// reports whether the shifted combinations can still be expressed as minutes
+// and hours, along with the day shifts (bit 0 for the previous day, bit 1
+// for the same day and bit 2 for the next day) of the combinations
+func shift(minutes, hours values, offsetMinutes int) (utcMinutes, utcHours values, dayShifts uint8, ok bool) {
+	combinations := map[int]bool{}
+	for _, h := range hours.list() {
+		for _, m := range minutes.list() {
+			t := h*minutesPerHour + m - offsetMinutes
+			dayShift := 0
+			for t < 0 {
+				t += minutesPerDay
+				dayShift--
+			}
+			for t >= minutesPerDay {
+				t -= minutesPerDay
+				dayShift++
+			}
+
+			combinations[t] = true
+			utcHours |= 1 << uint(t/minutesPerHour)
+			utcMinutes |= 1 << uint(t%minutesPerHour)
+			dayShifts |= 1 << uint(dayShift+1)
+		}
+	}
+
+	ok = bits.OnesCount64(uint64(utcMinutes))*bits.OnesCount64(uint64(utcHours)) == len(combinations)
+	return utcMinutes, utcHours, dayShifts, ok
+}
+
+func singleDayShift(dayShifts uint8) (int, bool) {
+	if bits.OnesCount8(dayShifts) != 1 {
+		return 0, false
+	}
+
+	return bits.TrailingZeros8(dayShifts) - 1, true
+}
+
+// shiftableDays reports whether the days of month stay in the same month when
+// shifted, for every month
+func shiftableDays(doms values, dayShift int) bool {
+	for _, day := range doms.list() {
+		if day > lastCommonDay || day+dayShift < domField.min || day+dayShift > lastCommonDay {
+			return false
+		}
+	}
+
+	return true
+}
+
+func shiftDays(doms values, dayShift int) values {
+	var shifted values
+	for _, day := range doms.list() {
+		shifted |= 1 << uint(day+dayShift)
+	}
+
+	return shifted
+}
+
+func rotateWeekdays(dows values, dayShift int) values {
+	var rotated values
+	for _, day := range dows.list() {
+		rotated |= 1 << uint((day+dayShift+7)%7)
+	}
+
+	return rotated
+}
+
+// NextTransition returns the first time after now at which the UTC offset of
+// loc changes, and false when loc has no further transitions
+func NextTransition(loc *time.Location, now time.Time) (time.Time, bool) {
+	_, offset := now.In(loc).Zone()
+	t := now
+	for {
+		_, end := t.In(loc).ZoneBounds()
+		if end.IsZero() {
+			return time.Time{}, false
+		}
+
+		if _, o := end.In(loc).Zone(); o != offset {
+			return end, true
+		}
+
+		t = end
+	}
+}
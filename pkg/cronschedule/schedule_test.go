@@ -0,0 +1,97 @@
+/*
+Copyright © 2026 Yonatan Kahana
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cronschedule
+
+import (
+	"testing"
+	"time"
+)
+
+var (
+	summer = time.Date(2026, time.July, 1, 12, 0, 0, 0, time.UTC)
+	winter = time.Date(2026, time.January, 15, 12, 0, 0, 0, time.UTC)
+)
+
+func testLocation(t *testing.T, name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("failed to load timezone %s: %v", name, err)
+	}
+
+	return loc
+}
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		name      string
+		schedule  string
+		timezone  string
+		now       time.Time
+		want      string
+		wantExact bool
+		wantErr   bool
+	}{
+		{name: "summer time", schedule: "0 9 * * *", timezone: "Europe/Berlin", now: summer, want: "0 7 * * *", wantExact: true},
+		{name: "winter time", schedule: "0 9 * * *", timezone: "Europe/Berlin", now: winter, want: "0 8 * * *", wantExact: true},
+		{name: "utc", schedule: "0 9 * * *", timezone: "UTC", now: winter, want: "0 9 * * *", wantExact: true},
+		{name: "descriptor moving to the previous day", schedule: "@daily", timezone: "Europe/Berlin", now: winter, want: "0 23 * * *", wantExact: true},
+		{name: "unchanged descriptor", schedule: "@hourly", timezone: "Europe/Berlin", now: winter, want: "@hourly", wantExact: true},
+		{name: "interval", schedule: "@every 1h", timezone: "Europe/Berlin", now: winter, want: "@every 1h", wantExact: true},
+		{name: "weekdays on the same day", schedule: "30 1 * * 1-5", timezone: "Europe/Berlin", now: winter, want: "30 0 * * 1-5", wantExact: true},
+		{name: "weekdays moving to the previous day", schedule: "30 0 * * MON-FRI", timezone: "Europe/Berlin", now: winter, want: "30 23 * * 0-4", wantExact: true},
+		{name: "weekday moving to the next day", schedule: "0 22 * * FRI", timezone: "America/New_York", now: summer, want: "0 2 * * 6", wantExact: true},
+		{name: "sunday as 7", schedule: "0 0 * * 7", timezone: "Europe/Berlin", now: winter, want: "0 23 * * 6", wantExact: true},
+		{name: "day of month moving to the previous day", schedule: "0 0 15 * *", timezone: "Europe/Berlin", now: winter, want: "0 23 14 * *", wantExact: true},
+		{name: "first day of month moving to the previous month", schedule: "0 0 1 * *", timezone: "Europe/Berlin", now: winter, want: "0 23 1 * *", wantExact: false},
+		{name: "hours across midnight with weekdays", schedule: "0 0-3 * * 1", timezone: "Europe/Berlin", now: summer, want: "0 0,1,22,23 * * 1", wantExact: false},
+		{name: "steps of hours", schedule: "0 */6 * * *", timezone: "Europe/Berlin", now: summer, want: "0 4,10,16,22 * * *", wantExact: true},
+		{name: "half hour offset", schedule: "0 9 * * *", timezone: "Asia/Kolkata", now: winter, want: "30 3 * * *", wantExact: true},
+		{name: "half hour offset with every quarter", schedule: "*/15 * * * *", timezone: "Asia/Kolkata", now: winter, want: "*/15 * * * *", wantExact: true},
+		{name: "half hour offset splitting minutes across hours", schedule: "0,45 9 * * *", timezone: "Asia/Kolkata", now: winter, want: "0,45 4 * * *", wantExact: false},
+		{name: "schedule with a timezone", schedule: "CRON_TZ=Europe/Berlin 0 9 * * *", timezone: "Europe/Berlin", now: winter, wantErr: true},
+		{name: "missing fields", schedule: "0 9 *", timezone: "Europe/Berlin", now: winter, wantErr: true},
+		{name: "invalid minute", schedule: "60 9 * * *", timezone: "Europe/Berlin", now: winter, wantErr: true},
+		{name: "invalid range", schedule: "0 9-3 * * *", timezone: "Europe/Berlin", now: winter, wantErr: true},
+		{name: "invalid step", schedule: "*/0 9 * * *", timezone: "Europe/Berlin", now: winter, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, exact, err := Translate(tt.schedule, testLocation(t, tt.timezone), tt.now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Translate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Translate() = %q, want %q", got, tt.want)
+			}
+			if exact != tt.wantExact {
+				t.Errorf("Translate() exact = %t, want %t", exact, tt.wantExact)
+			}
+		})
+	}
+}
+
+func TestNextTransition(t *testing.T) {
+	got, ok := NextTransition(testLocation(t, "Europe/Berlin"), winter)
+	if want := time.Date(2026, time.March, 29, 1, 0, 0, 0, time.UTC); !ok || !got.Equal(want) {
+		t.Errorf("NextTransition() = %s, %t, want %s", got, ok, want)
+	}
+
+	if got, ok := NextTransition(testLocation(t, "UTC"), winter); ok {
+		t.Errorf("NextTransition() = %s, want no transition", got)
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright © 2026 Yonatan Kahana
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inject
+
+import (
+	"fmt"
+	"strings"
+
+	k8tz "github.com/k8tz/k8tz/pkg"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// ConfigMapLocalTimeKey is the binaryData key holding the TZif file in
+	// the ConfigMaps used by the configMap injection strategy
+	ConfigMapLocalTimeKey = "localtime"
+	// ConfigMapTimezoneKey is the data key holding the zone name in the
+	// ConfigMaps used by the configMap injection strategy
+	ConfigMapTimezoneKey = "timezone"
+)
+
+var configMapNameReplacer = strings.NewReplacer("/", ".", "_", "-", "+", "-plus-")
+
+// ConfigMapName returns the name of the ConfigMap holding the given timezone,
+// e.g. k8tz-america.new-york for America/New_York
+func ConfigMapName(timezone string) string {
+	return "k8tz-" + configMapNameReplacer.Replace(strings.ToLower(timezone))
+}
+
+func (g *PatchGenerator) createConfigMapPatches(spec *corev1.PodSpec, pathprefix string) k8tz.Patches {
+	var patches = k8tz.Patches{}
+
+	containers := len(spec.Containers)
+	if containers == 0 {
+		return patches
+	}
+
+	volumeName := g.resolveVolumeName(spec)
+
+	for containerId := 0; containerId < containers; containerId++ {
+		if g.isContainerSkipped(&spec.Containers[containerId]) {
+			continue
+		}
+
+		patches = append(patches, g.createContainerVolumeMountPatches(&spec.Containers[containerId], fmt.Sprintf("%s/containers/%d", pathprefix, containerId), volumeName)...)
+	}
+
+	patches = append(patches, g.removeOrphanedVolumes(spec, pathprefix)...)
+
+	if len(spec.Volumes) == 0 {
+		patches = append(patches, k8tz.Patch{
+			Op:    "add",
+			Path:  fmt.Sprintf("%s/volumes", pathprefix),
+			Value: []corev1.Volume{},
+		})
+	}
+
+	patches = append(patches, k8tz.Patch{
+		Op:   "add",
+		Path: fmt.Sprintf("%s/volumes/-", pathprefix),
+		Value: corev1.Volume{
+			Name: volumeName,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: ConfigMapName(g.Timezone),
+					},
+				},
+			},
+		},
+	})
+
+	return patches
+}
@@ -0,0 +1,39 @@
+/*
+Copyright © 2026 Yonatan Kahana
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inject
+
+import "testing"
+
+func TestConfigMapName(t *testing.T) {
+	tests := []struct {
+		timezone string
+		want     string
+	}{
+		{timezone: "UTC", want: "k8tz-utc"},
+		{timezone: "Europe/London", want: "k8tz-europe.london"},
+		{timezone: "America/Argentina/Buenos_Aires", want: "k8tz-america.argentina.buenos-aires"},
+		{timezone: "Etc/GMT+5", want: "k8tz-etc.gmt-plus-5"},
+		{timezone: "Etc/GMT-5", want: "k8tz-etc.gmt-5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.timezone, func(t *testing.T) {
+			if got := ConfigMapName(tt.timezone); got != tt.want {
+				t.Errorf("ConfigMapName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
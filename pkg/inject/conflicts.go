@@ -0,0 +1,177 @@
+/*
+Copyright © 2026 Yonatan Kahana
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inject
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	k8tz "github.com/k8tz/k8tz/pkg"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// This file detects container volume mounts that overlap the paths k8tz
+// mounts into containers. A mount overlaps when it is mounted at a managed
+// path or anywhere below it, e.g. /usr/share/zoneinfo/Europe overlaps the
+// zoneinfo directory mount. How overlaps are resolved is decided by the
+// generator's MountConflictPolicy.
+
+// MountConflictPolicy decides what happens to containers that already have
+// volume mounts overlapping the paths mounted by k8tz
+type MountConflictPolicy string
+
+const (
+	// DefaultMountConflictPolicy is the default mount conflict policy of k8tz
+	DefaultMountConflictPolicy = ReplaceMountConflictPolicy
+	// ReplaceMountConflictPolicy removes the overlapping mounts, and volumes
+	// that are left without any mount, before k8tz mounts are added
+	ReplaceMountConflictPolicy MountConflictPolicy = "replace"
+	// SkipMountConflictPolicy leaves containers with overlapping mounts
+	// untouched, while the rest of the containers are injected as usual
+	SkipMountConflictPolicy MountConflictPolicy = "skip"
+	// RejectMountConflictPolicy fails the injection when any container has an
+	// overlapping mount
+	RejectMountConflictPolicy MountConflictPolicy = "reject"
+)
+
+// managedMountPaths returns the container paths that k8tz mounts into
+func (g *PatchGenerator) managedMountPaths() []string {
+	if !g.usesVolume() {
+		return nil
+	}
+
+	if g.Strategy == HostPathInjectionStrategy && !g.isHostTimezone() {
+		// the timezone file is not mounted from the nodes' zoneinfo
+		return []string{g.LocalTimePath, g.zoneinfoMountPath()}
+	}
+
+	return []string{g.LocalTimePath, g.zoneinfoMountPath(), g.TimezoneFilePath}
+}
+
+// isConflictingVolumeMount reports whether the mount is at, or below, one of
+// the paths managed by k8tz
+func (g *PatchGenerator) isConflictingVolumeMount(volumeMount corev1.VolumeMount) bool {
+	for _, managedPath := range g.managedMountPaths() {
+		if isOverlappingMountPath(volumeMount.MountPath, managedPath) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (g *PatchGenerator) hasConflictingVolumeMounts(volumeMounts []corev1.VolumeMount) bool {
+	for _, volumeMount := range volumeMounts {
+		if g.isConflictingVolumeMount(volumeMount) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isContainerSkipped reports whether the container should be left untouched
+// because of the skip mount conflict policy
+func (g *PatchGenerator) isContainerSkipped(container *corev1.Container) bool {
+	return g.MountConflictPolicy == SkipMountConflictPolicy && g.hasConflictingVolumeMounts(container.VolumeMounts)
+}
+
+// checkMountConflicts validates the mount conflict policy and, for the reject
+// policy, returns an error describing the first conflicting mount found
+func (g *PatchGenerator) checkMountConflicts(spec *corev1.PodSpec) error {
+	switch g.MountConflictPolicy {
+	case "", ReplaceMountConflictPolicy, SkipMountConflictPolicy:
+		return nil
+	case RejectMountConflictPolicy:
+		for _, container := range spec.Containers {
+			for _, volumeMount := range container.VolumeMounts {
+				if g.isConflictingVolumeMount(volumeMount) {
+					return fmt.Errorf("container %s has volume mount %s at %s which overlaps a path mounted by k8tz", container.Name, volumeMount.Name, volumeMount.MountPath)
+				}
+			}
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("unknown mount conflict policy specified: %s", g.MountConflictPolicy)
+}
+
+// removeOrphanedVolumes removes pod volumes whose every mount was removed by
+// removeContainerVolumeMounts; volumes that were unused in the first place are
+// left as-is
+func (g *PatchGenerator) removeOrphanedVolumes(spec *corev1.PodSpec, pathprefix string) k8tz.Patches {
+	patches := k8tz.Patches{}
+
+	removed := map[string]bool{}
+	referenced := map[string]bool{}
+	for containerId := range spec.Containers {
+		container := &spec.Containers[containerId]
+		skipped := g.isContainerSkipped(container)
+		for _, volumeMount := range container.VolumeMounts {
+			if !skipped && g.isConflictingVolumeMount(volumeMount) {
+				removed[volumeMount.Name] = true
+			} else {
+				referenced[volumeMount.Name] = true
+			}
+		}
+	}
+
+	if len(removed) == 0 {
+		return patches
+	}
+
+	for _, container := range spec.InitContainers {
+		for _, volumeMount := range container.VolumeMounts {
+			referenced[volumeMount.Name] = true
+		}
+	}
+
+	for _, container := range spec.EphemeralContainers {
+		for _, volumeMount := range container.VolumeMounts {
+			referenced[volumeMount.Name] = true
+		}
+	}
+
+	for index := len(spec.Volumes) - 1; index >= 0; index-- {
+		name := spec.Volumes[index].Name
+		if removed[name] && !referenced[name] {
+			patches = append(patches, k8tz.Patch{
+				Op:    "remove",
+				Path:  fmt.Sprintf("%s/volumes/%d", pathprefix, index),
+				Value: "",
+			})
+		}
+	}
+
+	return patches
+}
+
+func isOverlappingMountPath(mountPath string, managedPath string) bool {
+	if mountPath == "" || managedPath == "" {
+		return false
+	}
+
+	mountPath = path.Clean(mountPath)
+	managedPath = path.Clean(managedPath)
+	if mountPath == managedPath {
+		return true
+	}
+
+	return strings.HasPrefix(mountPath, strings.TrimSuffix(managedPath, "/")+"/")
+}
@@ -0,0 +1,204 @@
+/*
+Copyright © 2026 Yonatan Kahana
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inject
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	k8tz "github.com/k8tz/k8tz/pkg"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func Test_isOverlappingMountPath(t *testing.T) {
+	tests := []struct {
+		name        string
+		mountPath   string
+		managedPath string
+		want        bool
+	}{
+		{name: "same path", mountPath: "/usr/share/zoneinfo", managedPath: "/usr/share/zoneinfo", want: true},
+		{name: "trailing slash", mountPath: "/usr/share/zoneinfo/", managedPath: "/usr/share/zoneinfo", want: true},
+		{name: "nested path", mountPath: "/usr/share/zoneinfo/Europe/London", managedPath: "/usr/share/zoneinfo", want: true},
+		{name: "parent path", mountPath: "/usr/share", managedPath: "/usr/share/zoneinfo", want: false},
+		{name: "sibling with common prefix", mountPath: "/usr/share/zoneinfo-extra", managedPath: "/usr/share/zoneinfo", want: false},
+		{name: "unrelated path", mountPath: "/data", managedPath: "/etc/localtime", want: false},
+		{name: "empty managed path", mountPath: "/data", managedPath: "", want: false},
+		{name: "timezone file", mountPath: "/etc/timezone", managedPath: "/etc/timezone", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isOverlappingMountPath(tt.mountPath, tt.managedPath); got != tt.want {
+				t.Errorf("isOverlappingMountPath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPatchGenerator_checkMountConflicts(t *testing.T) {
+	spec := &corev1.PodSpec{
+		Containers: []corev1.Container{
+			{
+				Name: "app",
+				VolumeMounts: []corev1.VolumeMount{
+					{Name: "tzdata", MountPath: "/usr/share/zoneinfo/Europe"},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		policy  MountConflictPolicy
+		wantErr bool
+	}{
+		{name: "unset policy replaces", policy: "", wantErr: false},
+		{name: "replace policy", policy: ReplaceMountConflictPolicy, wantErr: false},
+		{name: "skip policy", policy: SkipMountConflictPolicy, wantErr: false},
+		{name: "reject policy", policy: RejectMountConflictPolicy, wantErr: true},
+		{name: "unknown policy", policy: "moo", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &PatchGenerator{
+				LocalTimePath:       "/etc/localtime",
+				MountConflictPolicy: tt.policy,
+			}
+			if err := g.checkMountConflicts(spec); (err != nil) != tt.wantErr {
+				t.Errorf("PatchGenerator.checkMountConflicts() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPatchGenerator_removeOrphanedVolumes(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy MountConflictPolicy
+		spec   *corev1.PodSpec
+		want   k8tz.Patches
+	}{
+		{
+			name: "volume without remaining mounts is removed",
+			spec: &corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Name: "app",
+						VolumeMounts: []corev1.VolumeMount{
+							{Name: "data", MountPath: "/data"},
+							{Name: "tz", MountPath: "/etc/localtime"},
+							{Name: "tz", MountPath: "/usr/share/zoneinfo/Asia"},
+						},
+					},
+				},
+				Volumes: []corev1.Volume{{Name: "tz"}, {Name: "data"}, {Name: "unused"}},
+			},
+			want: k8tz.Patches{
+				{Op: "remove", Path: "/spec/volumes/0", Value: ""},
+			},
+		},
+		{
+			name: "volume still mounted elsewhere is kept",
+			spec: &corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Name: "app",
+						VolumeMounts: []corev1.VolumeMount{
+							{Name: "shared", MountPath: "/usr/share/zoneinfo"},
+							{Name: "shared", MountPath: "/shared"},
+						},
+					},
+				},
+				InitContainers: []corev1.Container{
+					{
+						Name: "init",
+						VolumeMounts: []corev1.VolumeMount{
+							{Name: "init-tz", MountPath: "/etc/localtime"},
+						},
+					},
+				},
+				Volumes: []corev1.Volume{{Name: "shared"}, {Name: "init-tz"}},
+			},
+			want: k8tz.Patches{},
+		},
+		{
+			name:   "skipped containers keep their volumes",
+			policy: SkipMountConflictPolicy,
+			spec: &corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Name: "app",
+						VolumeMounts: []corev1.VolumeMount{
+							{Name: "tz", MountPath: "/etc/localtime"},
+						},
+					},
+				},
+				Volumes: []corev1.Volume{{Name: "tz"}},
+			},
+			want: k8tz.Patches{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &PatchGenerator{
+				LocalTimePath:       "/etc/localtime",
+				MountConflictPolicy: tt.policy,
+			}
+			if got := g.removeOrphanedVolumes(tt.spec, "/spec"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PatchGenerator.removeOrphanedVolumes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPatchGenerator_skipMountConflictPolicy(t *testing.T) {
+	g := &PatchGenerator{
+		Strategy:            HostPathInjectionStrategy,
+		Timezone:            "UTC",
+		HostPathPrefix:      "/usr/share/zoneinfo",
+		LocalTimePath:       "/etc/localtime",
+		MountConflictPolicy: SkipMountConflictPolicy,
+	}
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "conflicting",
+					VolumeMounts: []corev1.VolumeMount{
+						{Name: "tz", MountPath: "/etc/localtime"},
+					},
+				},
+				{
+					Name: "clean",
+				},
+			},
+			Volumes: []corev1.Volume{{Name: "tz"}},
+		},
+	}
+
+	got, err := g.Generate(pod, "")
+	if err != nil {
+		t.Fatalf("PatchGenerator.Generate() error = %v", err)
+	}
+
+	for _, patch := range got {
+		if strings.HasPrefix(patch.Path, "/spec/containers/0/") {
+			t.Errorf("PatchGenerator.Generate() patched skipped container: %+v", patch)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+/*
+Copyright © 2026 Yonatan Kahana
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inject
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func (g *PatchGenerator) checkEmptyDir() error {
+	switch g.EmptyDirMedium {
+	case corev1.StorageMediumDefault, corev1.StorageMediumMemory:
+	default:
+		return fmt.Errorf("unsupported emptyDir medium specified: %s", g.EmptyDirMedium)
+	}
+
+	if g.EmptyDirSizeLimit != "" {
+		if _, err := resource.ParseQuantity(g.EmptyDirSizeLimit); err != nil {
+			return fmt.Errorf("invalid emptyDir size limit specified: %s, error=%w", g.EmptyDirSizeLimit, err)
+		}
+	}
+
+	return nil
+}
+
+// emptyDirVolumeSource returns the volume that the bootstrap initContainer
+// copies the zoneinfo files to
+func (g *PatchGenerator) emptyDirVolumeSource() *corev1.EmptyDirVolumeSource {
+	source := &corev1.EmptyDirVolumeSource{
+		Medium: g.EmptyDirMedium,
+	}
+
+	if g.EmptyDirSizeLimit != "" {
+		sizeLimit := resource.MustParse(g.EmptyDirSizeLimit)
+		source.SizeLimit = &sizeLimit
+	}
+
+	return source
+}
@@ -0,0 +1,117 @@
+/*
+Copyright © 2026 Yonatan Kahana
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inject
+
+import (
+	"fmt"
+
+	k8tz "github.com/k8tz/k8tz/pkg"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// This file injects ephemeral containers, which `kubectl debug` adds to
+// running pods through the pods/ephemeralcontainers subresource. Only the
+// containers added by the update are patched since existing ephemeral
+// containers cannot be changed, and the volume of the original injection is
+// reused since pod volumes cannot be added after creation.
+
+// GenerateEphemeralContainers returns the patches that inject the ephemeral
+// containers of pod that are not in oldPod
+func (g *PatchGenerator) GenerateEphemeralContainers(pod *corev1.Pod, oldPod *corev1.Pod, pathprefix string) (k8tz.Patches, error) {
+	var patches = k8tz.Patches{}
+
+	if err := g.checkZoneinfoMountPath(); err != nil {
+		return nil, err
+	}
+
+	if err := g.checkRuntimeProfile(); err != nil {
+		return nil, err
+	}
+
+	existing := map[string]bool{}
+	for _, container := range oldPod.Spec.EphemeralContainers {
+		existing[container.Name] = true
+	}
+
+	windows := isWindowsPodSpec(&pod.Spec)
+	windowsTimezone, injectWindows := "", false
+	if windows {
+		windowsTimezone, injectWindows = g.windowsTimezone()
+	}
+
+	volumeName := ""
+	if g.usesVolume() && !windows {
+		volumeName = g.injectedVolumeName(&pod.Spec)
+		if volumeName == "" {
+			k8tz.WarningLogger.Printf("could not find the volume of the %s injection of pod %s/%s, ephemeral containers get only the TZ environment variable", g.Strategy, pod.Namespace, pod.Name)
+		}
+	}
+
+	for containerId, ephemeralContainer := range pod.Spec.EphemeralContainers {
+		if existing[ephemeralContainer.Name] {
+			continue
+		}
+
+		container := corev1.Container(ephemeralContainer.EphemeralContainerCommon)
+		if g.isContainerInjected(&container) {
+			continue
+		}
+
+		containerPath := fmt.Sprintf("%s/spec/ephemeralContainers/%d", pathprefix, containerId)
+		if windows {
+			if injectWindows {
+				patches = append(patches, createWindowsContainerPatches(&container, containerPath, windowsTimezone)...)
+			}
+			continue
+		}
+
+		// a conflicting mount must not fail the debug session, so the reject
+		// policy skips the container as well
+		if g.MountConflictPolicy != ReplaceMountConflictPolicy && g.MountConflictPolicy != "" && g.hasConflictingVolumeMounts(container.VolumeMounts) {
+			k8tz.InfoLogger.Printf("skipping ephemeral container %s of pod %s/%s because of conflicting volume mounts", container.Name, pod.Namespace, pod.Name)
+			continue
+		}
+
+		if volumeMounts := g.ephemeralContainerVolumeMounts(volumeName); len(volumeMounts) > 0 {
+			patches = append(patches, g.createVolumeMountPatches(&container, containerPath, volumeMounts)...)
+		}
+
+		patches = append(patches, g.createContainerEnvironmentVariablePatches(&container, containerPath)...)
+	}
+
+	return patches, nil
+}
+
+// ephemeralContainerVolumeMounts returns the mounts of the k8tz volume that
+// ephemeral containers get. The API server rejects subPath mounts of
+// ephemeral containers, so only the mounts of a whole volume are kept, e.g.
+// the zoneinfo directory of the hostPath and initContainer strategies, and
+// the containers of other strategies rely on TZ alone
+func (g *PatchGenerator) ephemeralContainerVolumeMounts(volumeName string) []corev1.VolumeMount {
+	volumeMounts := []corev1.VolumeMount{}
+	if volumeName == "" {
+		return volumeMounts
+	}
+
+	for _, volumeMount := range g.containerVolumeMounts(volumeName) {
+		if volumeMount.SubPath == "" {
+			volumeMounts = append(volumeMounts, volumeMount)
+		}
+	}
+
+	return volumeMounts
+}
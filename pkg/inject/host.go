@@ -0,0 +1,115 @@
+/*
+Copyright © 2026 Yonatan Kahana
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inject
+
+import (
+	"fmt"
+
+	k8tz "github.com/k8tz/k8tz/pkg"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// This file implements the "host" timezone, where pods follow the local time
+// of the node they run on. The node's localtime file, and optionally its
+// timezone name file, are mounted with hostPath volumes regardless of the
+// injection strategy, and TZ is not set since the timezone is not known
+// before the pod is scheduled.
+
+const (
+	// HostLocalTimePath is the path of the node's localtime file
+	HostLocalTimePath = "/etc/localtime"
+	// HostTimezoneFilePath is the path of the node's timezone name file
+	HostTimezoneFilePath = "/etc/timezone"
+)
+
+func (g *PatchGenerator) isHostTimezone() bool {
+	return g.Timezone == k8tz.HostTimezone
+}
+
+// usesVolume reports whether the injection mounts a volume into containers
+func (g *PatchGenerator) usesVolume() bool {
+	return g.Strategy != EnvInjectionStrategy || g.isHostTimezone()
+}
+
+// checkHostTimezone refuses the host timezone when hostPath volumes are not
+// allowed. Unlike the hostPath strategy the pod is not admitted without
+// injection, since no other strategy can follow the node's local time.
+func (g *PatchGenerator) checkHostTimezone() error {
+	if g.isHostTimezone() && !g.PodSecurityLevel.AllowsHostPath() {
+		return fmt.Errorf("%s timezone uses hostPath volumes which are not allowed by the %s pod security level", k8tz.HostTimezone, g.PodSecurityLevel)
+	}
+
+	return nil
+}
+
+// hostTimezoneFileVolumeName returns the name of the volume of the node's
+// timezone name file, resolveVolumeName makes sure it is free in the pod
+func hostTimezoneFileVolumeName(volumeName string) string {
+	return volumeName + "-timezone"
+}
+
+func (g *PatchGenerator) createHostTimezonePatches(spec *corev1.PodSpec, pathprefix string) k8tz.Patches {
+	var patches = k8tz.Patches{}
+	containers := len(spec.Containers)
+	if containers == 0 {
+		return patches
+	}
+
+	volumeName := g.resolveVolumeName(spec)
+
+	for containerId := 0; containerId < containers; containerId++ {
+		if g.isContainerSkipped(&spec.Containers[containerId]) {
+			continue
+		}
+
+		patches = append(patches, g.createContainerVolumeMountPatches(&spec.Containers[containerId], fmt.Sprintf("%s/containers/%d", pathprefix, containerId), volumeName)...)
+	}
+
+	patches = append(patches, g.removeOrphanedVolumes(spec, pathprefix)...)
+
+	if len(spec.Volumes) == 0 {
+		patches = append(patches, k8tz.Patch{
+			Op:    "add",
+			Path:  fmt.Sprintf("%s/volumes", pathprefix),
+			Value: []corev1.Volume{},
+		})
+	}
+
+	hostPathVolume := func(name string, hostPath string) k8tz.Patch {
+		hostPathType := corev1.HostPathFile
+		return k8tz.Patch{
+			Op:   "add",
+			Path: fmt.Sprintf("%s/volumes/-", pathprefix),
+			Value: corev1.Volume{
+				Name: name,
+				VolumeSource: corev1.VolumeSource{
+					HostPath: &corev1.HostPathVolumeSource{
+						Path: hostPath,
+						Type: &hostPathType,
+					},
+				},
+			},
+		}
+	}
+
+	patches = append(patches, hostPathVolume(volumeName, HostLocalTimePath))
+	if g.TimezoneFilePath != "" {
+		patches = append(patches, hostPathVolume(hostTimezoneFileVolumeName(volumeName), HostTimezoneFilePath))
+	}
+
+	return patches
+}
@@ -0,0 +1,85 @@
+/*
+Copyright © 2026 Yonatan Kahana
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inject
+
+import (
+	"fmt"
+
+	k8tz "github.com/k8tz/k8tz/pkg"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// tzdataImage returns the image mounted by the imageVolume strategy, which
+// can be the data-only tzdata image instead of the bootstrap image
+func (g *PatchGenerator) tzdataImage() string {
+	if g.TzdataImage != "" {
+		return g.TzdataImage
+	}
+
+	return g.InitContainerImage
+}
+
+func (g *PatchGenerator) checkImagePullPolicy() error {
+	switch g.ImagePullPolicy {
+	case "", corev1.PullAlways, corev1.PullIfNotPresent, corev1.PullNever:
+		return nil
+	}
+
+	return fmt.Errorf("unknown image pull policy specified: %s", g.ImagePullPolicy)
+}
+
+// createImagePullSecretsPatches adds ImagePullSecrets that are not already
+// referenced by the pod, so the injected images can be pulled from private
+// registries
+func (g *PatchGenerator) createImagePullSecretsPatches(spec *corev1.PodSpec, pathprefix string) k8tz.Patches {
+	var patches = k8tz.Patches{}
+
+	existing := make(map[string]bool, len(spec.ImagePullSecrets))
+	for _, secret := range spec.ImagePullSecrets {
+		existing[secret.Name] = true
+	}
+
+	missing := []corev1.LocalObjectReference{}
+	for _, name := range g.ImagePullSecrets {
+		if name != "" && !existing[name] {
+			existing[name] = true
+			missing = append(missing, corev1.LocalObjectReference{Name: name})
+		}
+	}
+
+	if len(missing) == 0 {
+		return patches
+	}
+
+	if len(spec.ImagePullSecrets) == 0 {
+		patches = append(patches, k8tz.Patch{
+			Op:    "add",
+			Path:  fmt.Sprintf("%s/imagePullSecrets", pathprefix),
+			Value: []corev1.LocalObjectReference{},
+		})
+	}
+
+	for _, secret := range missing {
+		patches = append(patches, k8tz.Patch{
+			Op:    "add",
+			Path:  fmt.Sprintf("%s/imagePullSecrets/-", pathprefix),
+			Value: secret,
+		})
+	}
+
+	return patches
+}
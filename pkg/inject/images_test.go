@@ -0,0 +1,69 @@
+/*
+Copyright © 2026 Yonatan Kahana
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inject
+
+import (
+	"reflect"
+	"testing"
+
+	k8tz "github.com/k8tz/k8tz/pkg"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestPatchGenerator_createImagePullSecretsPatches(t *testing.T) {
+	tests := []struct {
+		name    string
+		secrets []string
+		spec    *corev1.PodSpec
+		want    k8tz.Patches
+	}{
+		{
+			name:    "no secrets configured",
+			secrets: nil,
+			spec:    &corev1.PodSpec{},
+			want:    k8tz.Patches{},
+		},
+		{
+			name:    "secrets added to pod without secrets",
+			secrets: []string{"registry", "mirror"},
+			spec:    &corev1.PodSpec{},
+			want: k8tz.Patches{
+				{Op: "add", Path: "/spec/imagePullSecrets", Value: []corev1.LocalObjectReference{}},
+				{Op: "add", Path: "/spec/imagePullSecrets/-", Value: corev1.LocalObjectReference{Name: "registry"}},
+				{Op: "add", Path: "/spec/imagePullSecrets/-", Value: corev1.LocalObjectReference{Name: "mirror"}},
+			},
+		},
+		{
+			name:    "existing and duplicate secrets are skipped",
+			secrets: []string{"registry", "mirror", "mirror"},
+			spec: &corev1.PodSpec{
+				ImagePullSecrets: []corev1.LocalObjectReference{{Name: "registry"}},
+			},
+			want: k8tz.Patches{
+				{Op: "add", Path: "/spec/imagePullSecrets/-", Value: corev1.LocalObjectReference{Name: "mirror"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &PatchGenerator{ImagePullSecrets: tt.secrets}
+			if got := g.createImagePullSecretsPatches(tt.spec, "/spec"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PatchGenerator.createImagePullSecretsPatches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
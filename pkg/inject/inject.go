@@ -19,6 +19,7 @@ package inject
 import (
 	"encoding/json"
 	"fmt"
+	"path"
 	"strconv"
 	"strings"
 
@@ -39,8 +40,23 @@ const (
 	DefaultHostPathPrefix string = "/usr/share/zoneinfo"
 	DefaultLocalTimePath  string = "/etc/localtime"
 
+	// DefaultZoneinfoMountPath is the default path of the zoneinfo directory
+	// mounted into containers
+	DefaultZoneinfoMountPath string = "/usr/share/zoneinfo"
+	// NoZoneinfoMountPath can be used as ZoneinfoMountPath to mount only the
+	// TZif file at LocalTimePath, keeping the image's own zoneinfo directory
+	NoZoneinfoMountPath string = "none"
+	// DefaultTimezoneFilePath is the conventional path of the file holding
+	// the zone name, mounted only when TimezoneFilePath is set
+	DefaultTimezoneFilePath string = "/etc/timezone"
+	// TimezoneFilesDir is the directory, relative to the zoneinfo root, that
+	// holds a file with the zone name for every zone (see bootstrap)
+	TimezoneFilesDir string = ".timezone"
+
 	// DefaultInitContainerName is the default name for initContainer of k8tz
 	DefaultInitContainerName string = "k8tz"
+	// DefaultVolumeName is the default name for the volume added by k8tz
+	DefaultVolumeName string = "k8tz"
 	// DefaultInjectionStrategy is the default injection strategy of k8tz
 	DefaultInjectionStrategy = InitContainerInjectionStrategy
 	// InitContainerInjectionStrategy is an injection strategy where we inject
@@ -55,6 +71,21 @@ const (
 	// ImageVolumeInjectionStrategy will use image reference as volume
 	// and mount the files directly from the image
 	ImageVolumeInjectionStrategy InjectionStrategy = "imageVolume"
+	// ImageVolumeLocalTimeMinVersion is the first Kubernetes version where
+	// image volumes support subPath to a single file, which is required for
+	// mounting the TZif file at LocalTimePath with the imageVolume strategy
+	ImageVolumeLocalTimeMinVersion = "1.35.0"
+	// ConfigMapInjectionStrategy mounts the TZif file from a per-timezone
+	// ConfigMap maintained by k8tz in the pod's namespace; the zoneinfo
+	// directory is not mounted since it does not fit in a ConfigMap
+	ConfigMapInjectionStrategy InjectionStrategy = "configMap"
+	// EnvInjectionStrategy only sets the TZ environment variable, for images
+	// that already ship the required tzdata
+	EnvInjectionStrategy InjectionStrategy = "env"
+	// AutoInjectionStrategy lets the webhook pick a strategy supported by the
+	// cluster and the pod's namespace, it cannot be used for generating
+	// patches directly
+	AutoInjectionStrategy InjectionStrategy = "auto"
 )
 
 var (
@@ -74,6 +105,27 @@ type PatchGenerator struct {
 	HostPathPrefix         string
 	LocalTimePath          string
 	CronJobTimeZone        bool
+	CronJobPodTemplate     bool
+	CronJobScheduleRewrite bool
+	MountConflictPolicy    MountConflictPolicy
+	VolumeName             string
+	ZoneinfoMountPath      string
+	TimezoneFilePath       string
+	RuntimeProfile         RuntimeProfile
+	EnvTimezones           []string
+	ImageVolumeLocalTime   bool
+	TzdataImage            string
+	ImagePullPolicy        corev1.PullPolicy
+	ImagePullSecrets       []string
+	AnnotateStrategy       bool
+	PodSecurityLevel       PodSecurityLevel
+	EmptyDirSizeLimit      string
+	EmptyDirMedium         corev1.StorageMedium
+	Reinvocation           bool
+	WindowsPolicy          WindowsPolicy
+	WindowsTimezones       map[string]string
+	// Warnings collects the warnings of the generated patches (output only)
+	Warnings []string
 }
 
 func NewPatchGenerator() PatchGenerator {
@@ -87,6 +139,13 @@ func NewPatchGenerator() PatchGenerator {
 		HostPathPrefix:         DefaultHostPathPrefix,
 		LocalTimePath:          DefaultLocalTimePath,
 		CronJobTimeZone:        false,
+		CronJobPodTemplate:     false,
+		CronJobScheduleRewrite: false,
+		MountConflictPolicy:    DefaultMountConflictPolicy,
+		VolumeName:             DefaultVolumeName,
+		ZoneinfoMountPath:      DefaultZoneinfoMountPath,
+		RuntimeProfile:         DefaultRuntimeProfile,
+		WindowsPolicy:          DefaultWindowsPolicy,
 	}
 }
 
@@ -160,6 +219,61 @@ func (g *PatchGenerator) handleList(list *corev1.List, pathprefix string) (patch
 }
 
 func (g *PatchGenerator) forPodSpec(spec *corev1.PodSpec, pathprefix string, postInjectionAnnotations map[string]*metav1.ObjectMeta) (patches k8tz.Patches, err error) {
+	if err := g.checkZoneinfoMountPath(); err != nil {
+		return nil, err
+	}
+
+	if err := g.checkTimezoneFilePath(); err != nil {
+		return nil, err
+	}
+
+	if err := g.checkRuntimeProfile(); err != nil {
+		return nil, err
+	}
+
+	if err := g.checkImagePullPolicy(); err != nil {
+		return nil, err
+	}
+
+	if err := g.checkEmptyDir(); err != nil {
+		return nil, err
+	}
+
+	if err := g.checkWindowsPolicy(); err != nil {
+		return nil, err
+	}
+
+	if isWindowsPodSpec(spec) {
+		return g.createWindowsPatches(spec, pathprefix, postInjectionAnnotations), nil
+	}
+
+	if err := g.checkHostTimezone(); err != nil {
+		return nil, err
+	}
+
+	if err := g.checkPodSecurity(); err != nil {
+		return nil, err
+	}
+
+	if g.Reinvocation {
+		return g.createReinvocationPatches(spec, pathprefix)
+	}
+
+	if err := g.checkMountConflicts(spec); err != nil {
+		return nil, err
+	}
+
+	if g.isHostTimezone() {
+		// the node's localtime is mounted whatever the strategy is, and there
+		// is no TZ to set
+		patches = append(patches, g.createHostTimezonePatches(spec, pathprefix)...)
+		for k, v := range postInjectionAnnotations {
+			patches = append(patches, g.createPostInjectionAnnotations(v, k)...)
+		}
+
+		return patches, nil
+	}
+
 	switch g.Strategy {
 	case HostPathInjectionStrategy:
 		patches = append(patches, g.createHostPathPatches(spec, pathprefix)...)
@@ -171,6 +285,14 @@ func (g *PatchGenerator) forPodSpec(spec *corev1.PodSpec, pathprefix string, pos
 		patches = append(patches, initPaches...)
 	case ImageVolumeInjectionStrategy:
 		patches = append(patches, g.createImageVolumePatches(spec, pathprefix)...)
+	case ConfigMapInjectionStrategy:
+		patches = append(patches, g.createConfigMapPatches(spec, pathprefix)...)
+	case EnvInjectionStrategy:
+		if err := g.checkEnvTimezone(); err != nil {
+			return nil, err
+		}
+	case AutoInjectionStrategy:
+		return nil, fmt.Errorf("%s injection strategy is only supported by the webhook", g.Strategy)
 	default:
 		return nil, fmt.Errorf("unknown injection strategy specified: %s", g.Strategy)
 	}
@@ -185,17 +307,89 @@ func (g *PatchGenerator) forPodSpec(spec *corev1.PodSpec, pathprefix string, pos
 }
 
 func (g *PatchGenerator) forCronJobSpec(spec *batchv1.CronJobSpec, pathprefix string, postInjectionAnnotations map[string]*metav1.ObjectMeta) (patches k8tz.Patches, err error) {
+	patches = k8tz.Patches{}
+
+	if g.CronJobPodTemplate {
+		templatePatches, err := g.forJobTemplate(spec, pathprefix)
+		if err != nil {
+			return nil, err
+		}
+
+		patches = append(patches, templatePatches...)
+	}
+
 	if g.CronJobTimeZone {
-		patches = append(patches, g.createCronJobPatches(spec, pathprefix)...)
+		if g.isHostTimezone() {
+			// without timeZone the schedule already follows the local time of
+			// the controller manager's node
+			k8tz.InfoLogger.Printf("%s timezone does not set the timeZone of cronJobs", k8tz.HostTimezone)
+		} else {
+			if g.Strategy == EnvInjectionStrategy {
+				if err := g.checkEnvTimezone(); err != nil {
+					return nil, err
+				}
+			}
+
+			patches = append(patches, g.createCronJobPatches(spec, pathprefix)...)
+		}
+	}
+
+	var scheduleMetadataPatches k8tz.Patches
+	if g.CronJobScheduleRewrite {
+		if err := g.checkCronJobScheduleRewrite(); err != nil {
+			return nil, err
+		}
 
+		schedulePatches, metadataPatches, err := g.createScheduleRewritePatches(spec, pathprefix, postInjectionAnnotations)
+		if err != nil {
+			return nil, err
+		}
+
+		patches = append(patches, schedulePatches...)
+		scheduleMetadataPatches = metadataPatches
+	}
+
+	if len(patches) > 0 {
 		for k, v := range postInjectionAnnotations {
 			patches = append(patches, g.createPostInjectionAnnotations(v, k)...)
 		}
+
+		patches = append(patches, scheduleMetadataPatches...)
 	}
 
 	return patches, nil
 }
 
+// forJobTemplate injects the pod template of the cronJob's jobs, unless it is
+// already injected
+func (g *PatchGenerator) forJobTemplate(spec *batchv1.CronJobSpec, pathprefix string) (k8tz.Patches, error) {
+	template := &spec.JobTemplate.Spec.Template
+	if isObjectInjected(&template.ObjectMeta) {
+		return k8tz.Patches{}, nil
+	}
+
+	templatePath := fmt.Sprintf("%s/jobTemplate/spec/template", pathprefix)
+	podPatches, err := g.forPodSpec(&template.Spec, fmt.Sprintf("%s/spec", templatePath), map[string]*metav1.ObjectMeta{
+		fmt.Sprintf("%s/metadata", templatePath): &template.ObjectMeta,
+	})
+	if err != nil || len(podPatches) == 0 {
+		return podPatches, err
+	}
+
+	patches := k8tz.Patches{}
+	if len(template.Annotations) == 0 {
+		// the metadata of job templates is often omitted, so it is added
+		// (as is) before the post injection annotations
+		patches = append(patches, k8tz.Patch{
+			Op:    "add",
+			Path:  fmt.Sprintf("%s/metadata", templatePath),
+			Value: template.ObjectMeta,
+		})
+	}
+
+	return append(patches, podPatches...), nil
+}
+
 func (g *PatchGenerator) createCronJobPatches(spec *batchv1.CronJobSpec, pathprefix string) k8tz.Patches {
 	var patches = k8tz.Patches{}
 
@@ -212,41 +406,155 @@ func (g *PatchGenerator) createEnvironmentVariablePatches(spec *corev1.PodSpec,
 	var patches = k8tz.Patches{}
 
 	for containerId := 0; containerId < len(spec.Containers); containerId++ {
-		if len(spec.Containers[containerId].Env) == 0 {
-			patches = append(patches, k8tz.Patch{
-				Op:    "add",
-				Path:  fmt.Sprintf("%s/containers/%d/env", pathprefix, containerId),
-				Value: []corev1.EnvVar{},
-			})
+		if g.isContainerSkipped(&spec.Containers[containerId]) {
+			continue
 		}
 
+		patches = append(patches, g.createContainerEnvironmentVariablePatches(&spec.Containers[containerId], fmt.Sprintf("%s/containers/%d", pathprefix, containerId))...)
+	}
+
+	return patches
+}
+
+func (g *PatchGenerator) createContainerEnvironmentVariablePatches(container *corev1.Container, containerPath string) k8tz.Patches {
+	var patches = k8tz.Patches{}
+
+	if g.isHostTimezone() {
+		return patches
+	}
+
+	if len(container.Env) == 0 {
+		patches = append(patches, k8tz.Patch{
+			Op:    "add",
+			Path:  fmt.Sprintf("%s/env", containerPath),
+			Value: []corev1.EnvVar{},
+		})
+	}
+
+	patches = append(patches, k8tz.Patch{
+		Op:   "add",
+		Path: fmt.Sprintf("%s/env/-", containerPath),
+		Value: corev1.EnvVar{
+			Name:  "TZ",
+			Value: g.Timezone,
+		},
+	})
+
+	if zoneinfoPath := g.zoneinfoMountPath(); zoneinfoPath != "" && path.Clean(zoneinfoPath) != DefaultZoneinfoMountPath {
 		patches = append(patches, k8tz.Patch{
 			Op:   "add",
-			Path: fmt.Sprintf("%s/containers/%d/env/-", pathprefix, containerId),
+			Path: fmt.Sprintf("%s/env/-", containerPath),
 			Value: corev1.EnvVar{
-				Name:  "TZ",
-				Value: g.Timezone,
+				Name:  "TZDIR",
+				Value: zoneinfoPath,
 			},
 		})
 	}
 
+	patches = append(patches, g.createRuntimeProfilePatches(container, containerPath)...)
+
+	return patches
+}
+
+// createContainerVolumeMountPatches replaces the container mounts that
+// overlap the k8tz paths with the mounts of the injection strategy
+func (g *PatchGenerator) createContainerVolumeMountPatches(container *corev1.Container, containerPath string, volumeName string) k8tz.Patches {
+	return g.createVolumeMountPatches(container, containerPath, g.containerVolumeMounts(volumeName))
+}
+
+func (g *PatchGenerator) createVolumeMountPatches(container *corev1.Container, containerPath string, volumeMounts []corev1.VolumeMount) k8tz.Patches {
+	var patches = k8tz.Patches{}
+
+	if len(container.VolumeMounts) == 0 {
+		patches = append(patches, k8tz.Patch{
+			Op:    "add",
+			Path:  fmt.Sprintf("%s/volumeMounts", containerPath),
+			Value: []corev1.VolumeMount{},
+		})
+	}
+
+	patches = append(patches, g.removeContainerVolumeMounts(container.VolumeMounts, containerPath)...)
+
+	for _, volumeMount := range volumeMounts {
+		patches = append(patches, k8tz.Patch{
+			Op:    "add",
+			Path:  fmt.Sprintf("%s/volumeMounts/-", containerPath),
+			Value: volumeMount,
+		})
+	}
+
 	return patches
 }
 
-func (g *PatchGenerator) removeContainerVolumeMounts(volumeMounts []corev1.VolumeMount, pathprefix string, containerId int) k8tz.Patches {
+// containerVolumeMounts returns the mounts of the k8tz volume that the
+// injection strategy adds to every container
+func (g *PatchGenerator) containerVolumeMounts(volumeName string) []corev1.VolumeMount {
+	mount := func(mountPath string, subPath string) corev1.VolumeMount {
+		return corev1.VolumeMount{
+			Name:      volumeName,
+			ReadOnly:  true,
+			MountPath: mountPath,
+			SubPath:   subPath,
+		}
+	}
+
+	volumeMounts := []corev1.VolumeMount{}
+	if g.isHostTimezone() {
+		volumeMounts = append(volumeMounts, mount(g.LocalTimePath, ""))
+		if g.TimezoneFilePath != "" {
+			volumeMounts = append(volumeMounts, corev1.VolumeMount{
+				Name:      hostTimezoneFileVolumeName(volumeName),
+				ReadOnly:  true,
+				MountPath: g.TimezoneFilePath,
+			})
+		}
+
+		return volumeMounts
+	}
+
+	switch g.Strategy {
+	case HostPathInjectionStrategy:
+		// stock nodes have no timezone name files in their zoneinfo
+		// directory, so with hostPath the zone name is given only by TZ
+		volumeMounts = append(volumeMounts, mount(g.LocalTimePath, g.Timezone))
+		if zoneinfoPath := g.zoneinfoMountPath(); zoneinfoPath != "" {
+			volumeMounts = append(volumeMounts, mount(zoneinfoPath, ""))
+		}
+	case InitContainerInjectionStrategy:
+		volumeMounts = append(volumeMounts, mount(g.LocalTimePath, g.Timezone))
+		if g.TimezoneFilePath != "" {
+			volumeMounts = append(volumeMounts, mount(g.TimezoneFilePath, path.Join(TimezoneFilesDir, g.Timezone)))
+		}
+		if zoneinfoPath := g.zoneinfoMountPath(); zoneinfoPath != "" {
+			volumeMounts = append(volumeMounts, mount(zoneinfoPath, ""))
+		}
+	case ImageVolumeInjectionStrategy:
+		// older kubernetes versions does not support subPath of image volumes,
+		// in that case only the zoneinfo directory is mounted
+		if g.ImageVolumeLocalTime {
+			volumeMounts = append(volumeMounts, mount(g.LocalTimePath, path.Join("usr/share/zoneinfo", g.Timezone)))
+			if g.TimezoneFilePath != "" {
+				volumeMounts = append(volumeMounts, mount(g.TimezoneFilePath, path.Join("usr/share/zoneinfo", TimezoneFilesDir, g.Timezone)))
+			}
+		}
+		volumeMounts = append(volumeMounts, mount(g.zoneinfoMountPath(), "usr/share/zoneinfo/"))
+	case ConfigMapInjectionStrategy:
+		volumeMounts = append(volumeMounts, mount(g.LocalTimePath, ConfigMapLocalTimeKey))
+		if g.TimezoneFilePath != "" {
+			volumeMounts = append(volumeMounts, mount(g.TimezoneFilePath, ConfigMapTimezoneKey))
+		}
+	}
+
+	return volumeMounts
+}
+
+func (g *PatchGenerator) removeContainerVolumeMounts(volumeMounts []corev1.VolumeMount, containerPath string) k8tz.Patches {
 	patches := k8tz.Patches{}
 	for index := len(volumeMounts) - 1; index >= 0; index-- {
-		switch volumeMounts[index].MountPath {
-		case g.LocalTimePath:
+		if g.isConflictingVolumeMount(volumeMounts[index]) {
 			patches = append(patches, k8tz.Patch{
 				Op:    "remove",
-				Path:  fmt.Sprintf("%s/containers/%d/volumeMounts/%d", pathprefix, containerId, index),
-				Value: "",
-			})
-		case g.HostPathPrefix:
-			patches = append(patches, k8tz.Patch{
-				Op:    "remove",
-				Path:  fmt.Sprintf("%s/containers/%d/volumeMounts/%d", pathprefix, containerId, index),
+				Path:  fmt.Sprintf("%s/volumeMounts/%d", containerPath, index),
 				Value: "",
 			})
 		}
@@ -262,6 +570,8 @@ func (g *PatchGenerator) createImageVolumePatches(spec *corev1.PodSpec, pathpref
 		return patches
 	}
 
+	volumeName := g.resolveVolumeName(spec)
+
 	if len(spec.Volumes) == 0 {
 		patches = append(patches, k8tz.Patch{
 			Op:    "add",
@@ -274,53 +584,30 @@ func (g *PatchGenerator) createImageVolumePatches(spec *corev1.PodSpec, pathpref
 		Op:   "add",
 		Path: fmt.Sprintf("%s/volumes/-", pathprefix),
 		Value: corev1.Volume{
-			Name: "k8tz",
+			Name: volumeName,
 			VolumeSource: corev1.VolumeSource{
 				Image: &corev1.ImageVolumeSource{
-					Reference: g.InitContainerImage,
+					Reference:  g.tzdataImage(),
+					PullPolicy: g.ImagePullPolicy,
 				},
 			},
 		},
 	})
 
 	for containerId := 0; containerId < containers; containerId++ {
-		if len(spec.Containers[containerId].VolumeMounts) == 0 {
-			patches = append(patches, k8tz.Patch{
-				Op:    "add",
-				Path:  fmt.Sprintf("%s/containers/%d/volumeMounts", pathprefix, containerId),
-				Value: []corev1.VolumeMount{},
-			})
+		if g.isContainerSkipped(&spec.Containers[containerId]) {
+			continue
 		}
 
-		patches = append(patches, g.removeContainerVolumeMounts(spec.Containers[containerId].VolumeMounts, pathprefix, containerId)...)
-
-		// currently only directory subpath is supported,
-		// hopefully in future we can enable that:
-		// patches = append(patches, k8tz.Patch{
-		// 	Op:   "add",
-		// 	Path: fmt.Sprintf("%s/containers/%d/volumeMounts/-", pathprefix, containerId),
-		// 	Value: corev1.VolumeMount{
-		// 		Name:      "k8tz",
-		// 		ReadOnly:  true,
-		// 		MountPath: g.LocalTimePath,
-		// 		SubPath:   "usr/share/zoneinfo/" + g.Timezone,
-		// 	},
-		// })
-
-		patches = append(patches, k8tz.Patch{
-			Op:   "add",
-			Path: fmt.Sprintf("%s/containers/%d/volumeMounts/-", pathprefix, containerId),
-			Value: corev1.VolumeMount{
-				Name:      "k8tz",
-				ReadOnly:  true,
-				MountPath: "/usr/share/zoneinfo",
-				SubPath:   "usr/share/zoneinfo/",
-			},
-		})
+		patches = append(patches, g.createContainerVolumeMountPatches(&spec.Containers[containerId], fmt.Sprintf("%s/containers/%d", pathprefix, containerId), volumeName)...)
 	}
 
+	patches = append(patches, g.removeOrphanedVolumes(spec, pathprefix)...)
+	patches = append(patches, g.createImagePullSecretsPatches(spec, pathprefix)...)
+
 	return patches
 }
+
 func (g *PatchGenerator) createInitContainerPatches(spec *corev1.PodSpec, pathprefix string) (k8tz.Patches, error) {
 	var patches = k8tz.Patches{}
 
@@ -329,6 +616,8 @@ func (g *PatchGenerator) createInitContainerPatches(spec *corev1.PodSpec, pathpr
 		return patches, nil
 	}
 
+	volumeName := g.resolveVolumeName(spec)
+
 	if len(spec.Volumes) == 0 {
 		patches = append(patches, k8tz.Patch{
 			Op:    "add",
@@ -341,46 +630,23 @@ func (g *PatchGenerator) createInitContainerPatches(spec *corev1.PodSpec, pathpr
 		Op:   "add",
 		Path: fmt.Sprintf("%s/volumes/-", pathprefix),
 		Value: corev1.Volume{
-			Name: "k8tz",
+			Name: volumeName,
 			VolumeSource: corev1.VolumeSource{
-				EmptyDir: &corev1.EmptyDirVolumeSource{},
+				EmptyDir: g.emptyDirVolumeSource(),
 			},
 		},
 	})
 
 	for containerId := 0; containerId < containers; containerId++ {
-		if len(spec.Containers[containerId].VolumeMounts) == 0 {
-			patches = append(patches, k8tz.Patch{
-				Op:    "add",
-				Path:  fmt.Sprintf("%s/containers/%d/volumeMounts", pathprefix, containerId),
-				Value: []corev1.VolumeMount{},
-			})
+		if g.isContainerSkipped(&spec.Containers[containerId]) {
+			continue
 		}
 
-		patches = append(patches, g.removeContainerVolumeMounts(spec.Containers[containerId].VolumeMounts, pathprefix, containerId)...)
-
-		patches = append(patches, k8tz.Patch{
-			Op:   "add",
-			Path: fmt.Sprintf("%s/containers/%d/volumeMounts/-", pathprefix, containerId),
-			Value: corev1.VolumeMount{
-				Name:      "k8tz",
-				ReadOnly:  true,
-				MountPath: g.LocalTimePath,
-				SubPath:   g.Timezone,
-			},
-		})
-
-		patches = append(patches, k8tz.Patch{
-			Op:   "add",
-			Path: fmt.Sprintf("%s/containers/%d/volumeMounts/-", pathprefix, containerId),
-			Value: corev1.VolumeMount{
-				Name:      "k8tz",
-				ReadOnly:  true,
-				MountPath: "/usr/share/zoneinfo",
-			},
-		})
+		patches = append(patches, g.createContainerVolumeMountPatches(&spec.Containers[containerId], fmt.Sprintf("%s/containers/%d", pathprefix, containerId), volumeName)...)
 	}
 
+	patches = append(patches, g.removeOrphanedVolumes(spec, pathprefix)...)
+
 	if len(spec.InitContainers) == 0 {
 		patches = append(patches, k8tz.Patch{
 			Op:    "add",
@@ -393,6 +659,9 @@ func (g *PatchGenerator) createInitContainerPatches(spec *corev1.PodSpec, pathpr
 	if g.InitContainerVerbose {
 		bootstrapArgs = append(bootstrapArgs, "--verbose")
 	}
+	if g.TimezoneFilePath != "" {
+		bootstrapArgs = append(bootstrapArgs, "--timezone-files")
+	}
 
 	resources, err := g.populateResourceRequirements()
 	if err != nil {
@@ -402,23 +671,14 @@ func (g *PatchGenerator) createInitContainerPatches(spec *corev1.PodSpec, pathpr
 		Op:   "add",
 		Path: fmt.Sprintf("%s/initContainers/-", pathprefix),
 		Value: corev1.Container{
-			Name:  g.InitContainerName,
-			Image: g.InitContainerImage,
-			Args:  bootstrapArgs,
-			SecurityContext: &corev1.SecurityContext{
-				AllowPrivilegeEscalation: &False,
-				SeccompProfile: &corev1.SeccompProfile{
-					Type: "RuntimeDefault",
-				},
-				Capabilities: &corev1.Capabilities{
-					Drop: []corev1.Capability{
-						"ALL",
-					},
-				},
-			},
+			Name:            g.InitContainerName,
+			Image:           g.InitContainerImage,
+			ImagePullPolicy: g.ImagePullPolicy,
+			Args:            bootstrapArgs,
+			SecurityContext: g.initContainerSecurityContext(),
 			VolumeMounts: []corev1.VolumeMount{
 				{
-					Name:      "k8tz",
+					Name:      volumeName,
 					MountPath: "/mnt/zoneinfo",
 					ReadOnly:  false,
 				},
@@ -427,6 +687,8 @@ func (g *PatchGenerator) createInitContainerPatches(spec *corev1.PodSpec, pathpr
 		},
 	})
 
+	patches = append(patches, g.createImagePullSecretsPatches(spec, pathprefix)...)
+
 	return patches, nil
 }
 
@@ -437,39 +699,22 @@ func (g *PatchGenerator) createHostPathPatches(spec *corev1.PodSpec, pathprefix
 		return patches
 	}
 
-	for containerId := 0; containerId < containers; containerId++ {
-		if len(spec.Containers[containerId].VolumeMounts) == 0 {
-			patches = append(patches, k8tz.Patch{
-				Op:    "add",
-				Path:  fmt.Sprintf("%s/containers/%d/volumeMounts", pathprefix, containerId),
-				Value: []corev1.VolumeMount{},
-			})
-		}
+	volumeName := g.resolveVolumeName(spec)
 
-		patches = append(patches, g.removeContainerVolumeMounts(spec.Containers[containerId].VolumeMounts, pathprefix, containerId)...)
+	if g.TimezoneFilePath != "" {
+		k8tz.WarningLogger.Printf("timezone file %s is not mounted with %s injection strategy, the zone name is set only by TZ", g.TimezoneFilePath, g.Strategy)
+	}
 
-		patches = append(patches, k8tz.Patch{
-			Op:   "add",
-			Path: fmt.Sprintf("%s/containers/%d/volumeMounts/-", pathprefix, containerId),
-			Value: corev1.VolumeMount{
-				Name:      "k8tz",
-				ReadOnly:  true,
-				MountPath: g.LocalTimePath,
-				SubPath:   g.Timezone,
-			},
-		})
+	for containerId := 0; containerId < containers; containerId++ {
+		if g.isContainerSkipped(&spec.Containers[containerId]) {
+			continue
+		}
 
-		patches = append(patches, k8tz.Patch{
-			Op:   "add",
-			Path: fmt.Sprintf("%s/containers/%d/volumeMounts/-", pathprefix, containerId),
-			Value: corev1.VolumeMount{
-				Name:      "k8tz",
-				ReadOnly:  true,
-				MountPath: "/usr/share/zoneinfo",
-			},
-		})
+		patches = append(patches, g.createContainerVolumeMountPatches(&spec.Containers[containerId], fmt.Sprintf("%s/containers/%d", pathprefix, containerId), volumeName)...)
 	}
 
+	patches = append(patches, g.removeOrphanedVolumes(spec, pathprefix)...)
+
 	if len(spec.Volumes) == 0 {
 		patches = append(patches, k8tz.Patch{
 			Op:    "add",
@@ -482,7 +727,7 @@ func (g *PatchGenerator) createHostPathPatches(spec *corev1.PodSpec, pathprefix
 		Op:   "add",
 		Path: fmt.Sprintf("%s/volumes/-", pathprefix),
 		Value: corev1.Volume{
-			Name: "k8tz",
+			Name: volumeName,
 			VolumeSource: corev1.VolumeSource{
 				HostPath: &corev1.HostPathVolumeSource{
 					Path: g.HostPathPrefix,
@@ -506,18 +751,134 @@ func (g *PatchGenerator) createPostInjectionAnnotations(meta *metav1.ObjectMeta,
 
 	patches = append(patches, k8tz.Patch{
 		Op:    "add",
-		Path:  fmt.Sprintf("%s/annotations/%s", pathprefix, escapeJsonPointer(k8tz.InjectedAnnotation)),
+		Path:  fmt.Sprintf("%s/annotations/%s", pathprefix, EscapeJsonPointer(k8tz.InjectedAnnotation)),
 		Value: "true",
 	})
 	patches = append(patches, k8tz.Patch{
 		Op:    "add",
-		Path:  fmt.Sprintf("%s/annotations/%s", pathprefix, escapeJsonPointer(k8tz.TimezoneAnnotation)),
+		Path:  fmt.Sprintf("%s/annotations/%s", pathprefix, EscapeJsonPointer(k8tz.TimezoneAnnotation)),
 		Value: g.Timezone,
 	})
 
+	if g.AnnotateStrategy {
+		patches = append(patches, k8tz.Patch{
+			Op:    "add",
+			Path:  fmt.Sprintf("%s/annotations/%s", pathprefix, EscapeJsonPointer(k8tz.InjectedStrategyAnnotation)),
+			Value: string(g.Strategy),
+		})
+	}
+
 	return patches
 }
 
+// zoneinfoMountPath returns the container path where the zoneinfo directory
+// is mounted, or an empty string when the directory should not be mounted
+func (g *PatchGenerator) zoneinfoMountPath() string {
+	if g.Strategy == ConfigMapInjectionStrategy || g.Strategy == EnvInjectionStrategy || g.isHostTimezone() {
+		return ""
+	}
+
+	switch g.ZoneinfoMountPath {
+	case "":
+		return DefaultZoneinfoMountPath
+	case NoZoneinfoMountPath:
+		return ""
+	}
+
+	return g.ZoneinfoMountPath
+}
+
+func (g *PatchGenerator) checkZoneinfoMountPath() error {
+	zoneinfoPath := g.zoneinfoMountPath()
+	if zoneinfoPath == "" {
+		if g.Strategy == ImageVolumeInjectionStrategy {
+			return fmt.Errorf("zoneinfo mount path cannot be %s with %s injection strategy", NoZoneinfoMountPath, g.Strategy)
+		}
+
+		return nil
+	}
+
+	if !path.IsAbs(zoneinfoPath) {
+		return fmt.Errorf("zoneinfo mount path must be absolute: %s", zoneinfoPath)
+	}
+
+	return nil
+}
+
+func (g *PatchGenerator) checkTimezoneFilePath() error {
+	if g.TimezoneFilePath == "" {
+		return nil
+	}
+
+	if g.Strategy == ImageVolumeInjectionStrategy && !g.ImageVolumeLocalTime {
+		return fmt.Errorf("timezone file cannot be mounted with %s injection strategy without image volume subPath support", g.Strategy)
+	}
+
+	if g.Strategy == ImageVolumeInjectionStrategy && g.TzdataImage == "" {
+		// the published k8tz image has no timezone name files, they exist
+		// only in tzdata images built from the updated tzdata/Dockerfile
+		return fmt.Errorf("timezone file cannot be mounted with %s injection strategy from the k8tz image, set a tzdata image built with timezone name files", g.Strategy)
+	}
+
+	if !path.IsAbs(g.TimezoneFilePath) {
+		return fmt.Errorf("timezone file path must be absolute: %s", g.TimezoneFilePath)
+	}
+
+	return nil
+}
+
+// checkEnvTimezone verifies the timezone is in EnvTimezones, when set, since
+// with the env injection strategy it must already exist in the image
+func (g *PatchGenerator) checkEnvTimezone() error {
+	if len(g.EnvTimezones) == 0 {
+		return nil
+	}
+
+	for _, pattern := range g.EnvTimezones {
+		if matched, _ := path.Match(pattern, g.Timezone); matched {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("timezone %s is not allowed with %s injection strategy", g.Timezone, g.Strategy)
+}
+
+// resolveVolumeName returns the configured volume name, or the first free
+// name with a numeric suffix (e.g. k8tz-1) when the pod already has a volume
+// with that name, so the suffix is deterministic for a given pod spec. With
+// the host timezone the name of the timezone name file volume, which is
+// derived from it, must be free as well.
+func (g *PatchGenerator) resolveVolumeName(spec *corev1.PodSpec) string {
+	name := g.VolumeName
+	if name == "" {
+		name = DefaultVolumeName
+	}
+
+	taken := make(map[string]bool, len(spec.Volumes))
+	for _, volume := range spec.Volumes {
+		taken[volume.Name] = true
+	}
+
+	free := func(candidate string) bool {
+		if g.isHostTimezone() && g.TimezoneFilePath != "" && taken[hostTimezoneFileVolumeName(candidate)] {
+			return false
+		}
+		return !taken[candidate]
+	}
+
+	if free(name) {
+		return name
+	}
+
+	for suffix := 1; ; suffix++ {
+		candidate := fmt.Sprintf("%s-%d", name, suffix)
+		if free(candidate) {
+			k8tz.VerboseLogger.Printf("volume name %s is already in use, using %s instead", name, candidate)
+			return candidate
+		}
+	}
+}
+
 func (g *PatchGenerator) populateResourceRequirements() (*corev1.ResourceRequirements, error) {
 	if len(g.InitContainerResources) > 0 {
 		resourceRequirement := corev1.ResourceRequirements{}
@@ -533,7 +894,8 @@ func (g *PatchGenerator) populateResourceRequirements() (*corev1.ResourceRequire
 	return &corev1.ResourceRequirements{}, nil
 }
 
-// TODO: unit test
-func escapeJsonPointer(p string) string {
+// EscapeJsonPointer escapes a reference token of a JSON pointer, e.g. an
+// annotation key in the path of a patch
+func EscapeJsonPointer(p string) string {
 	return jsonPointerEscapeReplacer.Replace(p)
 }
@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"reflect"
 	"testing"
 
 	k8tz "github.com/k8tz/k8tz/pkg"
@@ -635,6 +636,7 @@ func TestPatchGenerator_createPostInjectionAnnotations(t *testing.T) {
 		Timezone           string
 		InitContainerImage string
 		HostPathPrefix     string
+		AnnotateStrategy   bool
 	}
 	type args struct {
 		meta       *metav1.ObjectMeta
@@ -659,6 +661,20 @@ func TestPatchGenerator_createPostInjectionAnnotations(t *testing.T) {
 			},
 			golden: "testdata/postinjectionannotations-patch.json",
 		},
+		{
+			name: "test post injection annotations with strategy",
+			fields: fields{
+				Strategy:         HostPathInjectionStrategy,
+				Timezone:         "America/Anguilla",
+				HostPathPrefix:   "/usr/share/zoneinfo",
+				AnnotateStrategy: true,
+			},
+			args: args{
+				pathprefix: "/spec",
+				meta:       &metav1.ObjectMeta{Name: "k8tz"},
+			},
+			golden: "testdata/postinjectionannotations-strategy-patch.json",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -667,6 +683,7 @@ func TestPatchGenerator_createPostInjectionAnnotations(t *testing.T) {
 				Timezone:           tt.fields.Timezone,
 				InitContainerImage: tt.fields.InitContainerImage,
 				HostPathPrefix:     tt.fields.HostPathPrefix,
+				AnnotateStrategy:   tt.fields.AnnotateStrategy,
 			}
 
 			got := g.createPostInjectionAnnotations(tt.args.meta, tt.args.pathprefix)
@@ -764,8 +781,62 @@ func Test_escapeJsonPointer(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := escapeJsonPointer(tt.args.p); got != tt.want {
-				t.Errorf("escapeJsonPointer() = %v, want %v", got, tt.want)
+			if got := EscapeJsonPointer(tt.args.p); got != tt.want {
+				t.Errorf("EscapeJsonPointer() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPatchGenerator_resolveVolumeName(t *testing.T) {
+	tests := []struct {
+		name             string
+		volumeName       string
+		timezone         string
+		timezoneFilePath string
+		volumes          []corev1.Volume
+		want             string
+	}{
+		{
+			name: "default name without volumes",
+			want: "k8tz",
+		},
+		{
+			name:             "taken timezone file volume name of host timezone gets a suffix",
+			timezone:         k8tz.HostTimezone,
+			timezoneFilePath: DefaultTimezoneFilePath,
+			volumes:          []corev1.Volume{{Name: "k8tz-timezone"}},
+			want:             "k8tz-1",
+		},
+		{
+			name:     "timezone file volume name is ignored without timezone file",
+			timezone: k8tz.HostTimezone,
+			volumes:  []corev1.Volume{{Name: "k8tz-timezone"}},
+			want:     "k8tz",
+		},
+		{
+			name:       "custom name",
+			volumeName: "timezone",
+			volumes:    []corev1.Volume{{Name: "k8tz"}},
+			want:       "timezone",
+		},
+		{
+			name:    "taken name gets a suffix",
+			volumes: []corev1.Volume{{Name: "data"}, {Name: "k8tz"}},
+			want:    "k8tz-1",
+		},
+		{
+			name:       "first free suffix is used",
+			volumeName: "tz",
+			volumes:    []corev1.Volume{{Name: "tz"}, {Name: "tz-1"}, {Name: "tz-3"}},
+			want:       "tz-2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &PatchGenerator{VolumeName: tt.volumeName, Timezone: tt.timezone, TimezoneFilePath: tt.timezoneFilePath}
+			if got := g.resolveVolumeName(&corev1.PodSpec{Volumes: tt.volumes}); got != tt.want {
+				t.Errorf("PatchGenerator.resolveVolumeName() = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -878,6 +949,44 @@ func Test_removeContainerVolume(t *testing.T) {
 				pathprefix: "/spec",
 			},
 		},
+		{
+			name: "test mounts below /usr/share/zoneinfo with a different host path prefix",
+			fields: fields{
+				Strategy:       HostPathInjectionStrategy,
+				Timezone:       "Asia/Shanghai",
+				HostPathPrefix: "/opt/zoneinfo",
+			},
+			args: args{
+				containerId: 0,
+				VolumeMount: []corev1.VolumeMount{
+					{
+						Name:      "zoneinfo",
+						MountPath: "/usr/share/zoneinfo",
+					},
+					{
+						Name:      "zoneinfo",
+						MountPath: "/usr/share/zoneinfo/Asia",
+					},
+					{
+						Name:      "data",
+						MountPath: "/usr/share/zoneinfo-data",
+					},
+				},
+				result: k8tz.Patches{
+					{
+						Op:    "remove",
+						Path:  "/spec/containers/0/volumeMounts/1",
+						Value: "",
+					},
+					{
+						Op:    "remove",
+						Path:  "/spec/containers/0/volumeMounts/0",
+						Value: "",
+					},
+				},
+				pathprefix: "/spec",
+			},
+		},
 		{
 			name: "test /etc/localtime and /usr/share/zoneinfo",
 			fields: fields{
@@ -922,7 +1031,7 @@ func Test_removeContainerVolume(t *testing.T) {
 				HostPathPrefix: tt.fields.HostPathPrefix,
 				LocalTimePath:  "/etc/localtime",
 			}
-			got = g.removeContainerVolumeMounts(tt.args.VolumeMount, tt.args.pathprefix, tt.args.containerId)
+			got = g.removeContainerVolumeMounts(tt.args.VolumeMount, fmt.Sprintf("%s/containers/%d", tt.args.pathprefix, tt.args.containerId))
 			if len(got) != len(tt.args.result) {
 				t.Fail()
 			}
@@ -934,3 +1043,38 @@ func Test_removeContainerVolume(t *testing.T) {
 		})
 	}
 }
+
+func Test_ephemeralContainerVolumeMounts(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy InjectionStrategy
+		timezone string
+		want     []string
+	}{
+		{name: "hostPath mounts only the zoneinfo directory", strategy: HostPathInjectionStrategy, timezone: "Europe/London", want: []string{"/usr/share/zoneinfo"}},
+		{name: "initContainer mounts only the zoneinfo directory", strategy: InitContainerInjectionStrategy, timezone: "Europe/London", want: []string{"/usr/share/zoneinfo"}},
+		{name: "imageVolume relies on TZ alone", strategy: ImageVolumeInjectionStrategy, timezone: "Europe/London", want: []string{}},
+		{name: "configMap relies on TZ alone", strategy: ConfigMapInjectionStrategy, timezone: "Europe/London", want: []string{}},
+		{name: "host timezone mounts the node's localtime", strategy: HostPathInjectionStrategy, timezone: k8tz.HostTimezone, want: []string{"/etc/localtime"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &PatchGenerator{
+				Strategy:          tt.strategy,
+				Timezone:          tt.timezone,
+				LocalTimePath:     "/etc/localtime",
+				ZoneinfoMountPath: DefaultZoneinfoMountPath,
+			}
+			got := []string{}
+			for _, volumeMount := range g.ephemeralContainerVolumeMounts("k8tz") {
+				if volumeMount.SubPath != "" {
+					t.Errorf("ephemeralContainerVolumeMounts() mount %s has subPath %s", volumeMount.MountPath, volumeMount.SubPath)
+				}
+				got = append(got, volumeMount.MountPath)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ephemeralContainerVolumeMounts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright © 2026 Yonatan Kahana
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inject
+
+import (
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// This file keeps the injected content compliant with the Pod Security
+// Standards enforced on the pod's namespace: the bootstrap initContainer is
+// hardened for the restricted level, and strategies the level does not
+// allow are refused.
+
+// PodSecurityLevel is a Pod Security Standards level, as set by the
+// pod-security.kubernetes.io/enforce namespace label
+type PodSecurityLevel string
+
+const (
+	// PodSecurityEnforceLabel is the namespace label of Pod Security Admission
+	// that holds the enforced PodSecurityLevel
+	PodSecurityEnforceLabel = "pod-security.kubernetes.io/enforce"
+
+	PrivilegedPodSecurityLevel PodSecurityLevel = "privileged"
+	BaselinePodSecurityLevel   PodSecurityLevel = "baseline"
+	RestrictedPodSecurityLevel PodSecurityLevel = "restricted"
+
+	// bootstrapUser is the non-root user of the k8tz image
+	bootstrapUser int64 = 1000
+)
+
+// ErrPodSecurityViolation is returned when the injection strategy is not
+// allowed by the PodSecurityLevel of the generator
+var ErrPodSecurityViolation = errors.New("injection violates pod security level")
+
+// AllowsHostPath reports whether hostPath volumes are allowed by the level,
+// an empty level means Pod Security Admission is not enforced
+func (l PodSecurityLevel) AllowsHostPath() bool {
+	return l == "" || l == PrivilegedPodSecurityLevel
+}
+
+// checkPodSecurity refuses strategies that would make the pod violate the
+// enforced PodSecurityLevel
+func (g *PatchGenerator) checkPodSecurity() error {
+	if g.Strategy == HostPathInjectionStrategy && !g.PodSecurityLevel.AllowsHostPath() {
+		return fmt.Errorf("%w: %s injection strategy uses a hostPath volume which is not allowed by the %s level, use the initContainer, imageVolume or configMap strategy instead", ErrPodSecurityViolation, g.Strategy, g.PodSecurityLevel)
+	}
+
+	return nil
+}
+
+func (g *PatchGenerator) initContainerSecurityContext() *corev1.SecurityContext {
+	securityContext := &corev1.SecurityContext{
+		AllowPrivilegeEscalation: &False,
+		SeccompProfile: &corev1.SeccompProfile{
+			Type: "RuntimeDefault",
+		},
+		Capabilities: &corev1.Capabilities{
+			Drop: []corev1.Capability{
+				"ALL",
+			},
+		},
+	}
+
+	if g.PodSecurityLevel == RestrictedPodSecurityLevel {
+		runAsUser := bootstrapUser
+		securityContext.RunAsNonRoot = &True
+		securityContext.RunAsUser = &runAsUser
+		securityContext.ReadOnlyRootFilesystem = &True
+	}
+
+	return securityContext
+}
@@ -0,0 +1,201 @@
+/*
+Copyright © 2026 Yonatan Kahana
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inject
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	k8tz "github.com/k8tz/k8tz/pkg"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// This file adds runtime specific environment variables on top of TZ, for
+// runtimes that do not (always) respect it, e.g. JVMs that need
+// -Duser.timezone or Go binaries in scratch images that need ZONEINFO.
+
+// RuntimeProfile is a named set of environment variables that is added to
+// containers in addition to TZ
+type RuntimeProfile string
+
+const (
+	// DefaultRuntimeProfile is the default runtime profile of k8tz
+	DefaultRuntimeProfile = NoRuntimeProfile
+	// NoRuntimeProfile adds no environment variables other than TZ
+	NoRuntimeProfile RuntimeProfile = "none"
+	// AutoRuntimeProfile selects the profile of each container by matching
+	// its image against runtimeProfileImagePatterns
+	AutoRuntimeProfile RuntimeProfile = "auto"
+	// JavaRuntimeProfile adds -Duser.timezone to JAVA_TOOL_OPTIONS
+	JavaRuntimeProfile RuntimeProfile = "java"
+	// GoRuntimeProfile sets ZONEINFO to the mounted zoneinfo directory
+	GoRuntimeProfile RuntimeProfile = "go"
+	// DotnetRuntimeProfile sets TZDIR to the mounted zoneinfo directory
+	DotnetRuntimeProfile RuntimeProfile = "dotnet"
+	// PythonRuntimeProfile sets PYTHONTZPATH to the mounted zoneinfo directory
+	PythonRuntimeProfile RuntimeProfile = "python"
+
+	javaToolOptionsEnv     = "JAVA_TOOL_OPTIONS"
+	javaUserTimezoneOption = "-Duser.timezone="
+)
+
+// runtimeProfileImagePatterns are matched, in order, against every path
+// component of the image repository when AutoRuntimeProfile is used, e.g.
+// both "dotnet" and "runtime" for mcr.microsoft.com/dotnet/runtime:8.0
+var runtimeProfileImagePatterns = []struct {
+	pattern string
+	profile RuntimeProfile
+}{
+	{pattern: "*jdk*", profile: JavaRuntimeProfile},
+	{pattern: "*jre*", profile: JavaRuntimeProfile},
+	{pattern: "*java*", profile: JavaRuntimeProfile},
+	{pattern: "*temurin*", profile: JavaRuntimeProfile},
+	{pattern: "*corretto*", profile: JavaRuntimeProfile},
+	{pattern: "*zulu*", profile: JavaRuntimeProfile},
+	{pattern: "tomcat*", profile: JavaRuntimeProfile},
+	{pattern: "*dotnet*", profile: DotnetRuntimeProfile},
+	{pattern: "*aspnet*", profile: DotnetRuntimeProfile},
+	{pattern: "python*", profile: PythonRuntimeProfile},
+	{pattern: "golang*", profile: GoRuntimeProfile},
+}
+
+func (g *PatchGenerator) checkRuntimeProfile() error {
+	switch g.RuntimeProfile {
+	case "", NoRuntimeProfile, AutoRuntimeProfile, JavaRuntimeProfile, GoRuntimeProfile, DotnetRuntimeProfile, PythonRuntimeProfile:
+		return nil
+	}
+
+	return fmt.Errorf("unknown runtime profile specified: %s", g.RuntimeProfile)
+}
+
+// containerRuntimeProfile returns the runtime profile that applies to the
+// container, resolving AutoRuntimeProfile by the container's image
+func (g *PatchGenerator) containerRuntimeProfile(container *corev1.Container) RuntimeProfile {
+	if g.RuntimeProfile != AutoRuntimeProfile {
+		return g.RuntimeProfile
+	}
+
+	components := imageComponents(container.Image)
+	for _, p := range runtimeProfileImagePatterns {
+		for _, component := range components {
+			if matched, _ := path.Match(p.pattern, component); matched {
+				return p.profile
+			}
+		}
+	}
+
+	return NoRuntimeProfile
+}
+
+func (g *PatchGenerator) createRuntimeProfilePatches(container *corev1.Container, containerPath string) k8tz.Patches {
+	var patches = k8tz.Patches{}
+
+	zoneinfoPath := g.zoneinfoMountPath()
+	addEnv := func(name string, value string) {
+		patches = append(patches, k8tz.Patch{
+			Op:   "add",
+			Path: fmt.Sprintf("%s/env/-", containerPath),
+			Value: corev1.EnvVar{
+				Name:  name,
+				Value: value,
+			},
+		})
+	}
+
+	switch g.containerRuntimeProfile(container) {
+	case JavaRuntimeProfile:
+		patches = append(patches, g.createJavaToolOptionsPatches(container, containerPath)...)
+	case GoRuntimeProfile:
+		if zoneinfoPath != "" {
+			addEnv("ZONEINFO", zoneinfoPath)
+		}
+	case DotnetRuntimeProfile:
+		// TZDIR is already set when the zoneinfo path is not the default
+		if zoneinfoPath != "" && path.Clean(zoneinfoPath) == DefaultZoneinfoMountPath {
+			addEnv("TZDIR", zoneinfoPath)
+		}
+	case PythonRuntimeProfile:
+		if zoneinfoPath != "" {
+			addEnv("PYTHONTZPATH", zoneinfoPath)
+		}
+	}
+
+	return patches
+}
+
+// createJavaToolOptionsPatches adds -Duser.timezone to JAVA_TOOL_OPTIONS,
+// keeping any other options already set on the container
+func (g *PatchGenerator) createJavaToolOptionsPatches(container *corev1.Container, containerPath string) k8tz.Patches {
+	option := javaUserTimezoneOption + g.Timezone
+
+	for index, env := range container.Env {
+		if env.Name != javaToolOptionsEnv {
+			continue
+		}
+
+		if env.ValueFrom != nil {
+			k8tz.WarningLogger.Printf("%s of container %s is set from a reference, %s will not be added", javaToolOptionsEnv, container.Name, option)
+			return k8tz.Patches{}
+		}
+
+		return k8tz.Patches{{
+			Op:    "replace",
+			Path:  fmt.Sprintf("%s/env/%d/value", containerPath, index),
+			Value: mergeJavaToolOptions(env.Value, option),
+		}}
+	}
+
+	return k8tz.Patches{{
+		Op:   "add",
+		Path: fmt.Sprintf("%s/env/-", containerPath),
+		Value: corev1.EnvVar{
+			Name:  javaToolOptionsEnv,
+			Value: option,
+		},
+	}}
+}
+
+// mergeJavaToolOptions appends option to the existing options, replacing a
+// previous -Duser.timezone if there is one
+func mergeJavaToolOptions(options string, option string) string {
+	merged := []string{}
+	for _, o := range strings.Fields(options) {
+		if !strings.HasPrefix(o, javaUserTimezoneOption) {
+			merged = append(merged, o)
+		}
+	}
+
+	return strings.Join(append(merged, option), " ")
+}
+
+// imageComponents splits an image reference into the lower-cased path
+// components of its repository, without tag and digest, e.g.
+// "docker.io/library/openjdk:17@sha256:..." becomes [docker.io library openjdk]
+func imageComponents(image string) []string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+
+	components := strings.Split(strings.ToLower(image), "/")
+	last := components[len(components)-1]
+	if i := strings.Index(last, ":"); i >= 0 {
+		components[len(components)-1] = last[:i]
+	}
+
+	return components
+}
@@ -0,0 +1,110 @@
+/*
+Copyright © 2026 Yonatan Kahana
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inject
+
+import (
+	"reflect"
+	"testing"
+
+	k8tz "github.com/k8tz/k8tz/pkg"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func Test_imageComponents(t *testing.T) {
+	tests := []struct {
+		image string
+		want  []string
+	}{
+		{image: "nginx", want: []string{"nginx"}},
+		{image: "python:3.13-slim", want: []string{"python"}},
+		{image: "docker.io/library/eclipse-temurin:21-jre", want: []string{"docker.io", "library", "eclipse-temurin"}},
+		{image: "localhost:5000/team/OpenJDK@sha256:0123", want: []string{"localhost:5000", "team", "openjdk"}},
+		{image: "mcr.microsoft.com/dotnet/aspnet:8.0", want: []string{"mcr.microsoft.com", "dotnet", "aspnet"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			if got := imageComponents(tt.image); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("imageComponents() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPatchGenerator_containerRuntimeProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile RuntimeProfile
+		image   string
+		want    RuntimeProfile
+	}{
+		{name: "explicit profile ignores image", profile: GoRuntimeProfile, image: "eclipse-temurin:21", want: GoRuntimeProfile},
+		{name: "auto java", profile: AutoRuntimeProfile, image: "eclipse-temurin:21", want: JavaRuntimeProfile},
+		{name: "auto dotnet", profile: AutoRuntimeProfile, image: "mcr.microsoft.com/dotnet/runtime:8.0", want: DotnetRuntimeProfile},
+		{name: "auto python", profile: AutoRuntimeProfile, image: "python:3.13", want: PythonRuntimeProfile},
+		{name: "auto go", profile: AutoRuntimeProfile, image: "golang:1.24", want: GoRuntimeProfile},
+		{name: "auto unknown image", profile: AutoRuntimeProfile, image: "nginx", want: NoRuntimeProfile},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &PatchGenerator{RuntimeProfile: tt.profile}
+			if got := g.containerRuntimeProfile(&corev1.Container{Image: tt.image}); got != tt.want {
+				t.Errorf("PatchGenerator.containerRuntimeProfile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPatchGenerator_createJavaToolOptionsPatches(t *testing.T) {
+	tests := []struct {
+		name      string
+		container *corev1.Container
+		want      k8tz.Patches
+	}{
+		{
+			name:      "option is added when variable is missing",
+			container: &corev1.Container{Name: "app"},
+			want: k8tz.Patches{
+				{Op: "add", Path: "/spec/containers/0/env/-", Value: corev1.EnvVar{Name: "JAVA_TOOL_OPTIONS", Value: "-Duser.timezone=Europe/London"}},
+			},
+		},
+		{
+			name: "option is merged into existing value",
+			container: &corev1.Container{Name: "app", Env: []corev1.EnvVar{
+				{Name: "FOO", Value: "bar"},
+				{Name: "JAVA_TOOL_OPTIONS", Value: "-Xmx1g  -Duser.timezone=UTC -Dfile.encoding=UTF-8"},
+			}},
+			want: k8tz.Patches{
+				{Op: "replace", Path: "/spec/containers/0/env/1/value", Value: "-Xmx1g -Dfile.encoding=UTF-8 -Duser.timezone=Europe/London"},
+			},
+		},
+		{
+			name: "variable set from reference is left untouched",
+			container: &corev1.Container{Name: "app", Env: []corev1.EnvVar{
+				{Name: "JAVA_TOOL_OPTIONS", ValueFrom: &corev1.EnvVarSource{}},
+			}},
+			want: k8tz.Patches{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &PatchGenerator{Timezone: "Europe/London"}
+			if got := g.createJavaToolOptionsPatches(tt.container, "/spec/containers/0"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PatchGenerator.createJavaToolOptionsPatches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
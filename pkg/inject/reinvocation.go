@@ -0,0 +1,130 @@
+/*
+Copyright © 2026 Yonatan Kahana
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inject
+
+import (
+	"fmt"
+
+	k8tz "github.com/k8tz/k8tz/pkg"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// This file handles webhook reinvocation: when other mutating webhooks add
+// containers (e.g. service mesh sidecars) after k8tz injected the pod, the
+// webhook is called again and only the containers that were not injected yet
+// are patched, reusing the volume of the first injection.
+
+// isContainerInjected reports whether the container already has a timezone,
+// either from a previous injection or set by its owner. With the host
+// timezone, which sets no TZ, it is the mount of the node's localtime.
+func (g *PatchGenerator) isContainerInjected(container *corev1.Container) bool {
+	if g.isHostTimezone() {
+		for _, volumeMount := range container.VolumeMounts {
+			if isOverlappingMountPath(volumeMount.MountPath, g.LocalTimePath) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	for _, env := range container.Env {
+		if env.Name == "TZ" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// injectedVolumeName returns the name of the volume that the injected
+// containers mount, or an empty string when no container mounts it
+func (g *PatchGenerator) injectedVolumeName(spec *corev1.PodSpec) string {
+	managedPaths := map[string]bool{}
+	for _, volumeMount := range g.containerVolumeMounts("") {
+		managedPaths[volumeMount.MountPath] = true
+	}
+
+	for _, container := range spec.Containers {
+		if !g.isContainerInjected(&container) {
+			continue
+		}
+
+		for _, volumeMount := range container.VolumeMounts {
+			if managedPaths[volumeMount.MountPath] {
+				return volumeMount.Name
+			}
+		}
+	}
+
+	return ""
+}
+
+// createReinvocationPatches injects the containers that were added to an
+// already injected pod
+func (g *PatchGenerator) createReinvocationPatches(spec *corev1.PodSpec, pathprefix string) (k8tz.Patches, error) {
+	var patches = k8tz.Patches{}
+
+	switch g.Strategy {
+	case HostPathInjectionStrategy, InitContainerInjectionStrategy, ImageVolumeInjectionStrategy, ConfigMapInjectionStrategy, EnvInjectionStrategy:
+	default:
+		return nil, fmt.Errorf("unsupported injection strategy for reinvocation: %s", g.Strategy)
+	}
+
+	pending := []int{}
+	for containerId := range spec.Containers {
+		container := &spec.Containers[containerId]
+		if g.isContainerInjected(container) || g.isContainerSkipped(container) {
+			continue
+		}
+
+		if g.MountConflictPolicy == RejectMountConflictPolicy {
+			for _, volumeMount := range container.VolumeMounts {
+				if g.isConflictingVolumeMount(volumeMount) {
+					return nil, fmt.Errorf("container %s has volume mount %s at %s which overlaps a path mounted by k8tz", container.Name, volumeMount.Name, volumeMount.MountPath)
+				}
+			}
+		}
+
+		pending = append(pending, containerId)
+	}
+
+	if len(pending) == 0 {
+		return patches, nil
+	}
+
+	volumeName := ""
+	if g.usesVolume() {
+		volumeName = g.injectedVolumeName(spec)
+		if volumeName == "" {
+			return nil, fmt.Errorf("could not find the volume of the previous %s injection", g.Strategy)
+		}
+	}
+
+	// orphaned volumes are not removed since the mounts of the injected
+	// containers would be mistaken for conflicting mounts
+	for _, containerId := range pending {
+		containerPath := fmt.Sprintf("%s/containers/%d", pathprefix, containerId)
+		if volumeName != "" {
+			patches = append(patches, g.createContainerVolumeMountPatches(&spec.Containers[containerId], containerPath, volumeName)...)
+		}
+
+		patches = append(patches, g.createContainerEnvironmentVariablePatches(&spec.Containers[containerId], containerPath)...)
+	}
+
+	return patches, nil
+}
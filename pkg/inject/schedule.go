@@ -0,0 +1,112 @@
+/*
+Copyright © 2026 Yonatan Kahana
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inject
+
+import (
+	"fmt"
+	"time"
+
+	k8tz "github.com/k8tz/k8tz/pkg"
+	"github.com/k8tz/k8tz/pkg/cronschedule"
+
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func (g *PatchGenerator) checkCronJobScheduleRewrite() error {
+	if g.CronJobScheduleRewrite && g.CronJobTimeZone {
+		return fmt.Errorf("cronJob schedule rewrite cannot be combined with the timeZone of cronJobs")
+	}
+
+	return nil
+}
+
+// createScheduleRewritePatches rewrites the schedule of the cronJob to UTC,
+// for the current UTC offset of the timezone. The original and the rewritten
+// schedules are kept in annotations of the cronJob, which is labeled with the
+// ScheduleRewrittenLabel, so the cronschedule controller can follow the later
+// changes of the UTC offset. The webhook does not inject cronJobs twice, but
+// the CLI translates the original schedule of an already rewritten cronJob
+// again, unless its schedule was edited since. The metadata patches are
+// returned apart since they have to follow the post injection annotations
+func (g *PatchGenerator) createScheduleRewritePatches(spec *batchv1.CronJobSpec, pathprefix string, postInjectionAnnotations map[string]*metav1.ObjectMeta) (patches k8tz.Patches, metadataPatches k8tz.Patches, err error) {
+	if spec.TimeZone != nil {
+		k8tz.InfoLogger.Printf("cronJob already has a timeZone (%s), its schedule will not be rewritten", *spec.TimeZone)
+		return nil, nil, nil
+	}
+
+	if g.isHostTimezone() {
+		k8tz.InfoLogger.Printf("%s timezone does not rewrite the schedule of cronJobs", k8tz.HostTimezone)
+		return nil, nil, nil
+	}
+
+	original := spec.Schedule
+	for _, meta := range postInjectionAnnotations {
+		if v, ok := meta.Annotations[k8tz.OriginalScheduleAnnotation]; ok && meta.Annotations[k8tz.RewrittenScheduleAnnotation] == spec.Schedule {
+			original = v
+		}
+	}
+
+	loc, err := time.LoadLocation(g.Timezone)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load timezone %s: %w", g.Timezone, err)
+	}
+
+	schedule, exact, err := cronschedule.Translate(original, loc, time.Now())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to rewrite cronJob schedule: %w", err)
+	}
+
+	if !exact {
+		warning := fmt.Sprintf("schedule %q cannot be translated exactly from %s to UTC, using %q", original, g.Timezone, schedule)
+		k8tz.WarningLogger.Print(warning)
+		g.Warnings = append(g.Warnings, warning)
+	}
+
+	patches = append(patches, k8tz.Patch{
+		Op:    "replace",
+		Path:  fmt.Sprintf("%s/schedule", pathprefix),
+		Value: schedule,
+	})
+
+	for k, meta := range postInjectionAnnotations {
+		metadataPatches = append(metadataPatches, k8tz.Patch{
+			Op:    "add",
+			Path:  fmt.Sprintf("%s/annotations/%s", k, EscapeJsonPointer(k8tz.OriginalScheduleAnnotation)),
+			Value: original,
+		}, k8tz.Patch{
+			Op:    "add",
+			Path:  fmt.Sprintf("%s/annotations/%s", k, EscapeJsonPointer(k8tz.RewrittenScheduleAnnotation)),
+			Value: schedule,
+		})
+
+		if len(meta.Labels) == 0 {
+			metadataPatches = append(metadataPatches, k8tz.Patch{
+				Op:    "add",
+				Path:  fmt.Sprintf("%s/labels", k),
+				Value: map[string]string{},
+			})
+		}
+		metadataPatches = append(metadataPatches, k8tz.Patch{
+			Op:    "add",
+			Path:  fmt.Sprintf("%s/labels/%s", k, EscapeJsonPointer(k8tz.ScheduleRewrittenLabel)),
+			Value: "true",
+		})
+	}
+
+	return patches, metadataPatches, nil
+}
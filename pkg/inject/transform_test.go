@@ -68,6 +68,250 @@ func TestTransformer_Transform(t *testing.T) {
 			golden:  "testdata/test-pod-imageVolume-1.yaml",
 			wantErr: false,
 		},
+		{
+			name: "patch pod with imageVolume and localtime subPath",
+			fields: fields{
+				PatchGenerator: PatchGenerator{
+					Strategy:             ImageVolumeInjectionStrategy,
+					Timezone:             "Asia/Jerusalem",
+					InitContainerImage:   "k8tz:0.0.0",
+					LocalTimePath:        "/etc/localtime",
+					TimezoneFilePath:     DefaultTimezoneFilePath,
+					TzdataImage:          "registry.example.com/k8tz/tzdata:2026b",
+					ImageVolumeLocalTime: true,
+				},
+				Inputs: []string{"testdata/simple-pod.yaml"},
+			},
+			golden:  "testdata/test-pod-imageVolume-localtime.yaml",
+			wantErr: false,
+		},
+		{
+			name: "patch pod with imageVolume from tzdata image",
+			fields: fields{
+				PatchGenerator: PatchGenerator{
+					Strategy:           ImageVolumeInjectionStrategy,
+					Timezone:           "Asia/Jerusalem",
+					InitContainerImage: "k8tz:0.0.0",
+					TzdataImage:        "registry.example.com/k8tz/tzdata:2026b",
+					ImagePullPolicy:    corev1.PullIfNotPresent,
+					ImagePullSecrets:   []string{"registry-example-com"},
+				},
+				Inputs: []string{"testdata/simple-pod.yaml"},
+			},
+			golden:  "testdata/test-pod-imageVolume-tzdata-image.yaml",
+			wantErr: false,
+		},
+		{
+			name: "unknown image pull policy should raise an error",
+			fields: fields{
+				PatchGenerator: PatchGenerator{
+					Strategy:           InitContainerInjectionStrategy,
+					Timezone:           "Asia/Jerusalem",
+					InitContainerImage: "k8tz:0.0.0",
+					ImagePullPolicy:    "Sometimes",
+				},
+				Inputs: []string{"testdata/simple-pod.yaml"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "patch pod with initContainer in restricted namespace",
+			fields: fields{
+				PatchGenerator: PatchGenerator{
+					Strategy:           InitContainerInjectionStrategy,
+					Timezone:           "America/Jamaica",
+					InitContainerName:  "k8tz",
+					InitContainerImage: "testimage:0.0.0",
+					HostPathPrefix:     "/usr/share/zoneinfo",
+					LocalTimePath:      "/etc/localtime",
+					PodSecurityLevel:   RestrictedPodSecurityLevel,
+				},
+				Inputs: []string{"testdata/simple-pod.yaml"},
+			},
+			golden:  "testdata/test-pod-initContainer-restricted.yaml",
+			wantErr: false,
+		},
+		{
+			name: "patch pod with initContainer and emptyDir settings",
+			fields: fields{
+				PatchGenerator: PatchGenerator{
+					Strategy:           InitContainerInjectionStrategy,
+					Timezone:           "America/Jamaica",
+					InitContainerName:  "k8tz",
+					InitContainerImage: "testimage:0.0.0",
+					HostPathPrefix:     "/usr/share/zoneinfo",
+					LocalTimePath:      "/etc/localtime",
+					EmptyDirSizeLimit:  "4Mi",
+					EmptyDirMedium:     corev1.StorageMediumMemory,
+				},
+				Inputs: []string{"testdata/simple-pod.yaml"},
+			},
+			golden:  "testdata/test-pod-initContainer-emptydir.yaml",
+			wantErr: false,
+		},
+		{
+			name: "invalid emptyDir medium should raise an error",
+			fields: fields{
+				PatchGenerator: PatchGenerator{
+					Strategy:           InitContainerInjectionStrategy,
+					Timezone:           "Asia/Jerusalem",
+					InitContainerImage: "k8tz:0.0.0",
+					EmptyDirMedium:     "Disk",
+				},
+				Inputs: []string{"testdata/simple-pod.yaml"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "reinvocation should inject only the added containers",
+			fields: fields{
+				PatchGenerator: PatchGenerator{
+					Strategy:           InitContainerInjectionStrategy,
+					Timezone:           "America/Jamaica",
+					InitContainerName:  "k8tz",
+					InitContainerImage: "testimage:0.0.0",
+					HostPathPrefix:     "/usr/share/zoneinfo",
+					LocalTimePath:      "/etc/localtime",
+					VolumeName:         "k8tz",
+					ZoneinfoMountPath:  "/usr/share/zoneinfo",
+					Reinvocation:       true,
+				},
+				Inputs: []string{"testdata/pod-with-sidecar-injected.yaml"},
+			},
+			golden:  "testdata/test-pod-initContainer-reinvocation.yaml",
+			wantErr: false,
+		},
+		{
+			name: "reinvocation with env strategy should add only environment variables",
+			fields: fields{
+				PatchGenerator: PatchGenerator{
+					Strategy:     EnvInjectionStrategy,
+					Timezone:     "America/Jamaica",
+					Reinvocation: true,
+				},
+				Inputs: []string{"testdata/pod-with-sidecar-injected.yaml"},
+			},
+			golden:  "testdata/test-pod-env-reinvocation.yaml",
+			wantErr: false,
+		},
+		{
+			name: "reinvocation without the injected volume should raise an error",
+			fields: fields{
+				PatchGenerator: PatchGenerator{
+					Strategy:          HostPathInjectionStrategy,
+					Timezone:          "America/Jamaica",
+					HostPathPrefix:    "/usr/share/zoneinfo",
+					LocalTimePath:     "/opt/localtime",
+					ZoneinfoMountPath: NoZoneinfoMountPath,
+					Reinvocation:      true,
+				},
+				Inputs: []string{"testdata/pod-with-sidecar-injected.yaml"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "host timezone should mount the node's localtime without TZ",
+			fields: fields{
+				PatchGenerator: PatchGenerator{
+					Strategy:           InitContainerInjectionStrategy,
+					Timezone:           "host",
+					InitContainerImage: "k8tz:0.0.0",
+					LocalTimePath:      "/etc/localtime",
+					TimezoneFilePath:   "/etc/timezone",
+					VolumeName:         "k8tz",
+				},
+				Inputs: []string{"testdata/simple-pod.yaml"},
+			},
+			golden:  "testdata/test-pod-host-timezone.yaml",
+			wantErr: false,
+		},
+		{
+			name: "host timezone in baseline namespace should raise an error",
+			fields: fields{
+				PatchGenerator: PatchGenerator{
+					Strategy:         ConfigMapInjectionStrategy,
+					Timezone:         "host",
+					LocalTimePath:    "/etc/localtime",
+					PodSecurityLevel: BaselinePodSecurityLevel,
+				},
+				Inputs: []string{"testdata/simple-pod.yaml"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "windows pod should be skipped by default",
+			fields: fields{
+				PatchGenerator: PatchGenerator{
+					Strategy:       HostPathInjectionStrategy,
+					Timezone:       "Europe/London",
+					HostPathPrefix: "/usr/share/zoneinfo",
+					LocalTimePath:  "/etc/localtime",
+					WindowsPolicy:  SkipWindowsPolicy,
+				},
+				Inputs: []string{"testdata/windows-pod.yaml"},
+			},
+			golden:  "testdata/test-pod-windows-skip.yaml",
+			wantErr: false,
+		},
+		{
+			name: "windows pod with env policy should only set TZ",
+			fields: fields{
+				PatchGenerator: PatchGenerator{
+					Strategy:       HostPathInjectionStrategy,
+					Timezone:       "Europe/London",
+					HostPathPrefix: "/usr/share/zoneinfo",
+					LocalTimePath:  "/etc/localtime",
+					WindowsPolicy:  EnvWindowsPolicy,
+				},
+				Inputs: []string{"testdata/windows-pod.yaml"},
+			},
+			golden:  "testdata/test-pod-windows-env.yaml",
+			wantErr: false,
+		},
+		{
+			name: "windows pod with mapping policy should set TZ to the windows time zone",
+			fields: fields{
+				PatchGenerator: PatchGenerator{
+					Strategy:         HostPathInjectionStrategy,
+					Timezone:         "Europe/London",
+					HostPathPrefix:   "/usr/share/zoneinfo",
+					LocalTimePath:    "/etc/localtime",
+					WindowsPolicy:    MappingWindowsPolicy,
+					WindowsTimezones: map[string]string{"Europe/London": "GMT Standard Time"},
+				},
+				Inputs: []string{"testdata/windows-pod.yaml"},
+			},
+			golden:  "testdata/test-pod-windows-mapping.yaml",
+			wantErr: false,
+		},
+		{
+			name: "invalid windows policy should raise an error",
+			fields: fields{
+				PatchGenerator: PatchGenerator{
+					Strategy:       HostPathInjectionStrategy,
+					Timezone:       "Europe/London",
+					HostPathPrefix: "/usr/share/zoneinfo",
+					LocalTimePath:  "/etc/localtime",
+					WindowsPolicy:  "linux",
+				},
+				Inputs: []string{"testdata/windows-pod.yaml"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "hostPath in baseline namespace should raise an error",
+			fields: fields{
+				PatchGenerator: PatchGenerator{
+					Strategy:         HostPathInjectionStrategy,
+					Timezone:         "Asia/Jerusalem",
+					HostPathPrefix:   "/usr/share/zoneinfo",
+					LocalTimePath:    "/etc/localtime",
+					PodSecurityLevel: BaselinePodSecurityLevel,
+				},
+				Inputs: []string{"testdata/simple-pod.yaml"},
+			},
+			wantErr: true,
+		},
 		{
 			name: "invalid yaml file should raise an error",
 			fields: fields{
@@ -272,6 +516,313 @@ func TestTransformer_Transform(t *testing.T) {
 			golden:  "testdata/test-pod-volumeMounts-initContainer-result.yaml",
 			wantErr: false,
 		},
+		{
+			name: "patch pod with hostPath and custom zoneinfo mount path",
+			fields: fields{
+				PatchGenerator: PatchGenerator{
+					Strategy:          HostPathInjectionStrategy,
+					Timezone:          "Asia/Jerusalem",
+					HostPathPrefix:    "/usr/share/zoneinfo",
+					LocalTimePath:     "/etc/localtime",
+					ZoneinfoMountPath: "/opt/zoneinfo",
+				},
+				Inputs: []string{"testdata/simple-pod.yaml"},
+			},
+			golden:  "testdata/test-pod-hostPath-zoneinfo-path.yaml",
+			wantErr: false,
+		},
+		{
+			name: "patch pod with initContainer without zoneinfo directory mount",
+			fields: fields{
+				PatchGenerator: PatchGenerator{
+					Strategy:           InitContainerInjectionStrategy,
+					Timezone:           "America/Jamaica",
+					InitContainerName:  "k8tz",
+					InitContainerImage: "testimage:0.0.0",
+					HostPathPrefix:     "/usr/share/zoneinfo",
+					LocalTimePath:      "/etc/localtime",
+					ZoneinfoMountPath:  NoZoneinfoMountPath,
+				},
+				Inputs: []string{"testdata/test-pod-volumeMounts.yaml"},
+			},
+			golden:  "testdata/test-pod-initContainer-no-zoneinfo.yaml",
+			wantErr: false,
+		},
+		{
+			name: "imageVolume without zoneinfo directory mount should raise an error",
+			fields: fields{
+				PatchGenerator: PatchGenerator{
+					Strategy:           ImageVolumeInjectionStrategy,
+					Timezone:           "Asia/Jerusalem",
+					InitContainerImage: "k8tz:0.0.0",
+					ZoneinfoMountPath:  NoZoneinfoMountPath,
+				},
+				Inputs: []string{"testdata/simple-pod.yaml"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "relative zoneinfo mount path should raise an error",
+			fields: fields{
+				PatchGenerator: PatchGenerator{
+					Strategy:          HostPathInjectionStrategy,
+					Timezone:          "Asia/Jerusalem",
+					HostPathPrefix:    "/usr/share/zoneinfo",
+					LocalTimePath:     "/etc/localtime",
+					ZoneinfoMountPath: "zoneinfo",
+				},
+				Inputs: []string{"testdata/simple-pod.yaml"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "patch pod with initContainer and timezone file",
+			fields: fields{
+				PatchGenerator: PatchGenerator{
+					Strategy:           InitContainerInjectionStrategy,
+					Timezone:           "America/Jamaica",
+					InitContainerName:  "k8tz",
+					InitContainerImage: "testimage:0.0.0",
+					HostPathPrefix:     "/usr/share/zoneinfo",
+					LocalTimePath:      "/etc/localtime",
+					TimezoneFilePath:   DefaultTimezoneFilePath,
+				},
+				Inputs: []string{"testdata/simple-pod.yaml"},
+			},
+			golden:  "testdata/test-pod-initContainer-timezone-file.yaml",
+			wantErr: false,
+		},
+		{
+			name: "patch pod with hostPath and timezone file",
+			fields: fields{
+				PatchGenerator: PatchGenerator{
+					Strategy:         HostPathInjectionStrategy,
+					Timezone:         "Asia/Jerusalem",
+					HostPathPrefix:   "/usr/share/zoneinfo",
+					LocalTimePath:    "/etc/localtime",
+					TimezoneFilePath: DefaultTimezoneFilePath,
+				},
+				Inputs: []string{"testdata/simple-pod.yaml"},
+			},
+			golden:  "testdata/test-pod-hostPath-timezone-file.yaml",
+			wantErr: false,
+		},
+		{
+			name: "imageVolume with timezone file from the k8tz image should raise an error",
+			fields: fields{
+				PatchGenerator: PatchGenerator{
+					Strategy:             ImageVolumeInjectionStrategy,
+					Timezone:             "Asia/Jerusalem",
+					InitContainerImage:   "k8tz:0.0.0",
+					TimezoneFilePath:     DefaultTimezoneFilePath,
+					ImageVolumeLocalTime: true,
+				},
+				Inputs: []string{"testdata/simple-pod.yaml"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "imageVolume with timezone file should raise an error",
+			fields: fields{
+				PatchGenerator: PatchGenerator{
+					Strategy:           ImageVolumeInjectionStrategy,
+					Timezone:           "Asia/Jerusalem",
+					InitContainerImage: "k8tz:0.0.0",
+					TimezoneFilePath:   DefaultTimezoneFilePath,
+				},
+				Inputs: []string{"testdata/simple-pod.yaml"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "patch pod with auto runtime profile",
+			fields: fields{
+				PatchGenerator: PatchGenerator{
+					Strategy:       HostPathInjectionStrategy,
+					Timezone:       "Asia/Jerusalem",
+					HostPathPrefix: "/usr/share/zoneinfo",
+					LocalTimePath:  "/etc/localtime",
+					RuntimeProfile: AutoRuntimeProfile,
+				},
+				Inputs: []string{"testdata/runtime-profiles-pod.yaml"},
+			},
+			golden:  "testdata/runtime-profiles-pod-injected.yaml",
+			wantErr: false,
+		},
+		{
+			name: "unknown runtime profile should raise an error",
+			fields: fields{
+				PatchGenerator: PatchGenerator{
+					Strategy:       HostPathInjectionStrategy,
+					Timezone:       "Asia/Jerusalem",
+					HostPathPrefix: "/usr/share/zoneinfo",
+					LocalTimePath:  "/etc/localtime",
+					RuntimeProfile: "cobol",
+				},
+				Inputs: []string{"testdata/simple-pod.yaml"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "patch pod with configMap",
+			fields: fields{
+				PatchGenerator: PatchGenerator{
+					Strategy:         ConfigMapInjectionStrategy,
+					Timezone:         "America/New_York",
+					LocalTimePath:    "/etc/localtime",
+					TimezoneFilePath: DefaultTimezoneFilePath,
+				},
+				Inputs: []string{"testdata/test-pod-volumeMounts.yaml"},
+			},
+			golden:  "testdata/test-pod-configMap.yaml",
+			wantErr: false,
+		},
+		{
+			name: "patch pod with env",
+			fields: fields{
+				PatchGenerator: PatchGenerator{
+					Strategy:      EnvInjectionStrategy,
+					Timezone:      "Europe/Berlin",
+					LocalTimePath: "/etc/localtime",
+					EnvTimezones:  []string{"UTC", "Europe/*"},
+				},
+				Inputs: []string{"testdata/test-pod-volumeMounts.yaml"},
+			},
+			golden:  "testdata/test-pod-env.yaml",
+			wantErr: false,
+		},
+		{
+			name: "env with timezone missing from the allowlist should raise an error",
+			fields: fields{
+				PatchGenerator: PatchGenerator{
+					Strategy:     EnvInjectionStrategy,
+					Timezone:     "Asia/Tokyo",
+					EnvTimezones: []string{"UTC", "Europe/*"},
+				},
+				Inputs: []string{"testdata/simple-pod.yaml"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "cronjob pod template injection",
+			fields: fields{
+				PatchGenerator: PatchGenerator{
+					Strategy:           HostPathInjectionStrategy,
+					Timezone:           "Europe/Dublin",
+					HostPathPrefix:     "/usr/share/zoneinfo",
+					LocalTimePath:      "/etc/localtime",
+					CronJobPodTemplate: true,
+				},
+				Inputs: []string{"testdata/simple-cronjob.yaml"},
+			},
+			golden:  "testdata/simple-cronjob-pod-template.yaml",
+			wantErr: false,
+		},
+		{
+			name: "cronjob pod template injection with timeZone",
+			fields: fields{
+				PatchGenerator: PatchGenerator{
+					Strategy:           HostPathInjectionStrategy,
+					Timezone:           "Europe/Dublin",
+					HostPathPrefix:     "/usr/share/zoneinfo",
+					LocalTimePath:      "/etc/localtime",
+					CronJobTimeZone:    true,
+					CronJobPodTemplate: true,
+				},
+				Inputs: []string{"testdata/simple-cronjob.yaml"},
+			},
+			golden:  "testdata/simple-cronjob-pod-template-dublin.yaml",
+			wantErr: false,
+		},
+		{
+			name: "cronjob schedule rewrite",
+			fields: fields{
+				PatchGenerator: PatchGenerator{
+					Strategy:               HostPathInjectionStrategy,
+					Timezone:               "Asia/Tokyo",
+					HostPathPrefix:         "/usr/share/zoneinfo",
+					LocalTimePath:          "/etc/localtime",
+					CronJobScheduleRewrite: true,
+				},
+				Inputs: []string{"testdata/workday-cronjob.yaml"},
+			},
+			golden:  "testdata/workday-cronjob-tokyo.yaml",
+			wantErr: false,
+		},
+		{
+			name: "cronjob schedule rewrite of a rewritten cronjob should translate the original schedule",
+			fields: fields{
+				PatchGenerator: PatchGenerator{
+					Strategy:               HostPathInjectionStrategy,
+					Timezone:               "Asia/Tokyo",
+					HostPathPrefix:         "/usr/share/zoneinfo",
+					LocalTimePath:          "/etc/localtime",
+					CronJobScheduleRewrite: true,
+				},
+				Inputs: []string{"testdata/workday-cronjob-tokyo.yaml"},
+			},
+			golden:  "testdata/workday-cronjob-tokyo.yaml",
+			wantErr: false,
+		},
+		{
+			name: "cronjob schedule rewrite of an edited schedule should translate the new schedule",
+			fields: fields{
+				PatchGenerator: PatchGenerator{
+					Strategy:               HostPathInjectionStrategy,
+					Timezone:               "Asia/Tokyo",
+					HostPathPrefix:         "/usr/share/zoneinfo",
+					LocalTimePath:          "/etc/localtime",
+					CronJobScheduleRewrite: true,
+				},
+				Inputs: []string{"testdata/workday-cronjob-edited.yaml"},
+			},
+			golden:  "testdata/workday-cronjob-edited-tokyo.yaml",
+			wantErr: false,
+		},
+		{
+			name: "cronjob schedule rewrite with timeZone should raise an error",
+			fields: fields{
+				PatchGenerator: PatchGenerator{
+					Strategy:               HostPathInjectionStrategy,
+					Timezone:               "Asia/Tokyo",
+					HostPathPrefix:         "/usr/share/zoneinfo",
+					LocalTimePath:          "/etc/localtime",
+					CronJobTimeZone:        true,
+					CronJobScheduleRewrite: true,
+				},
+				Inputs: []string{"testdata/workday-cronjob.yaml"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "cronjob with env and timezone missing from the allowlist should raise an error",
+			fields: fields{
+				PatchGenerator: PatchGenerator{
+					Strategy:        EnvInjectionStrategy,
+					Timezone:        "Europe/Dublin",
+					CronJobTimeZone: true,
+					EnvTimezones:    []string{"UTC"},
+				},
+				Inputs: []string{"testdata/simple-cronjob.yaml"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "pod with existing volume named as the k8tz volume",
+			fields: fields{
+				PatchGenerator: PatchGenerator{
+					Strategy:           InitContainerInjectionStrategy,
+					Timezone:           "UTC",
+					InitContainerName:  "k8tz",
+					InitContainerImage: "testimage:0.0.0",
+					HostPathPrefix:     "/usr/share/zoneinfo",
+					LocalTimePath:      "/etc/localtime",
+				},
+				Inputs: []string{"testdata/pod-with-k8tz-volume.yaml"},
+			},
+			golden:  "testdata/pod-with-k8tz-volume-injected.yaml",
+			wantErr: false,
+		},
 		{
 			name: "patch pod with valid full compute resources for the initContainer",
 			fields: fields{
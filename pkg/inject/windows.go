@@ -0,0 +1,137 @@
+/*
+Copyright © 2026 Yonatan Kahana
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inject
+
+import (
+	"fmt"
+
+	k8tz "github.com/k8tz/k8tz/pkg"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// This file handles Windows pods, which cannot use the Linux paths that the
+// injection strategies mount. Windows pods are detected by spec.os.name or
+// by their kubernetes.io/os node selector, and are injected according to the
+// generator's WindowsPolicy instead of its strategy.
+
+// WindowsPolicy decides how Windows pods are injected
+type WindowsPolicy string
+
+const (
+	// DefaultWindowsPolicy is the default Windows policy of k8tz
+	DefaultWindowsPolicy = SkipWindowsPolicy
+	// SkipWindowsPolicy leaves Windows pods untouched
+	SkipWindowsPolicy WindowsPolicy = "skip"
+	// EnvWindowsPolicy sets only the TZ environment variable of Windows
+	// pods, to the IANA timezone
+	EnvWindowsPolicy WindowsPolicy = "env"
+	// MappingWindowsPolicy sets the TZ environment variable of Windows pods
+	// to the Windows time zone ID of the timezone, e.g. "GMT Standard Time"
+	// for Europe/London, from WindowsTimezones. Timezones without a Windows
+	// time zone ID are not injected.
+	MappingWindowsPolicy WindowsPolicy = "mapping"
+)
+
+func (g *PatchGenerator) checkWindowsPolicy() error {
+	switch g.WindowsPolicy {
+	case "", SkipWindowsPolicy, EnvWindowsPolicy, MappingWindowsPolicy:
+		return nil
+	}
+
+	return fmt.Errorf("unknown windows policy specified: %s", g.WindowsPolicy)
+}
+
+// isWindowsPodSpec reports whether the pod runs on Windows nodes
+func isWindowsPodSpec(spec *corev1.PodSpec) bool {
+	if spec.OS != nil {
+		return spec.OS.Name == corev1.Windows
+	}
+
+	return spec.NodeSelector[corev1.LabelOSStable] == string(corev1.Windows)
+}
+
+// windowsTimezone returns the value of TZ for Windows pods, or false when
+// Windows pods should not be injected
+func (g *PatchGenerator) windowsTimezone() (string, bool) {
+	if g.isHostTimezone() {
+		return "", false
+	}
+
+	switch g.WindowsPolicy {
+	case EnvWindowsPolicy:
+		return g.Timezone, true
+	case MappingWindowsPolicy:
+		if timezone, ok := g.WindowsTimezones[g.Timezone]; ok {
+			return timezone, true
+		}
+
+		k8tz.WarningLogger.Printf("no windows time zone ID for timezone %s, windows pod will not be injected", g.Timezone)
+	}
+
+	return "", false
+}
+
+// createWindowsPatches sets TZ on the Windows pod containers that do not
+// have it yet, including containers added on reinvocation
+func (g *PatchGenerator) createWindowsPatches(spec *corev1.PodSpec, pathprefix string, postInjectionAnnotations map[string]*metav1.ObjectMeta) k8tz.Patches {
+	var patches = k8tz.Patches{}
+
+	timezone, ok := g.windowsTimezone()
+	if !ok {
+		k8tz.InfoLogger.Printf("skipping windows pod with %s windows policy", g.WindowsPolicy)
+		return patches
+	}
+
+	for containerId := range spec.Containers {
+		container := &spec.Containers[containerId]
+		if g.isContainerInjected(container) {
+			continue
+		}
+
+		patches = append(patches, createWindowsContainerPatches(container, fmt.Sprintf("%s/containers/%d", pathprefix, containerId), timezone)...)
+	}
+
+	if !g.Reinvocation {
+		for k, v := range postInjectionAnnotations {
+			patches = append(patches, g.createPostInjectionAnnotations(v, k)...)
+		}
+	}
+
+	return patches
+}
+
+func createWindowsContainerPatches(container *corev1.Container, containerPath string, timezone string) k8tz.Patches {
+	var patches = k8tz.Patches{}
+
+	if len(container.Env) == 0 {
+		patches = append(patches, k8tz.Patch{
+			Op:    "add",
+			Path:  fmt.Sprintf("%s/env", containerPath),
+			Value: []corev1.EnvVar{},
+		})
+	}
+
+	return append(patches, k8tz.Patch{
+		Op:   "add",
+		Path: fmt.Sprintf("%s/env/-", containerPath),
+		Value: corev1.EnvVar{
+			Name:  "TZ",
+			Value: timezone,
+		},
+	})
+}
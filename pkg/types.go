@@ -27,16 +27,96 @@ const (
 	DefaultTimezone = UTCTimezone
 	// UTCTimezone is TZ database name for UTC timezone
 	UTCTimezone = "UTC"
+	// AutoTimezone is the timezone value that derives the timezone from the
+	// region or zone the pod is constrained to
+	AutoTimezone = "auto"
+	// HostTimezone is the timezone value that mounts the localtime of the
+	// node instead of a specific timezone
+	HostTimezone = "host"
 
 	// InjectedAnnotation is a meta object annotation that indicates whether
 	// object is already have k8tz timezone injected or not (output only)
 	InjectedAnnotation = "k8tz.io/injected"
+	// InjectedStrategyAnnotation records the strategy that was picked for
+	// the auto injection strategy (output only)
+	InjectedStrategyAnnotation = "k8tz.io/injected-strategy"
 	// TimezoneAnnotation TODO
 	TimezoneAnnotation = "k8tz.io/timezone"
 	// InjectionStrategyAnnotation TODO
 	InjectionStrategyAnnotation = "k8tz.io/strategy"
 	// InjectAnnotation TODO
 	InjectAnnotation = "k8tz.io/inject"
+	// AllowedTimezonesAnnotation restricts the timezones of a namespace to a
+	// comma separated list of timezones or patterns, e.g. "UTC,Europe/*",
+	// it is honored on namespaces only
+	AllowedTimezonesAnnotation = "k8tz.io/allowed-timezones"
+	// TimezonePolicyAnnotation decides what happens to timezones that are not
+	// in AllowedTimezonesAnnotation, "reject" (default) or "coerce"
+	TimezonePolicyAnnotation = "k8tz.io/timezone-policy"
+	// OriginalScheduleAnnotation records the schedule of a CronJob before it
+	// was rewritten to UTC (output only)
+	OriginalScheduleAnnotation = "k8tz.io/original-schedule"
+	// RewrittenScheduleAnnotation records the UTC schedule that k8tz last
+	// wrote to a CronJob, to detect later edits of the schedule (output only)
+	RewrittenScheduleAnnotation = "k8tz.io/rewritten-schedule"
+	// TimezoneRuleAnnotation records the timezone rule that matched the user
+	// creating a Job on its pod template, so its pods get the timezone of the
+	// rule at the precedence of rules (output only)
+	TimezoneRuleAnnotation = "k8tz.io/timezone-rule"
+	// ScheduleRewrittenLabel marks the CronJobs whose schedule is rewritten
+	// to UTC, so they can be listed with a label selector (output only)
+	ScheduleRewrittenLabel = "k8tz.io/schedule-rewritten"
+
+	// The following annotations override the matching webhook settings for
+	// a single workload or namespace, they are honored on workloads only
+	// when allowed by the webhook's --allowed-overrides flag, which allows
+	// the zoneinfo path, runtime profile and tzdata image by default
+
+	// ZoneinfoPathAnnotation overrides the path where the zoneinfo directory
+	// is mounted, or "none" to mount only the localtime file
+	ZoneinfoPathAnnotation = "k8tz.io/zoneinfo-path"
+	// RuntimeProfileAnnotation selects the runtime profile whose environment
+	// variables are added to containers in addition to TZ
+	RuntimeProfileAnnotation = "k8tz.io/runtime-profile"
+	// TzdataImageAnnotation overrides the image mounted by the imageVolume
+	// injection strategy
+	TzdataImageAnnotation = "k8tz.io/tzdata-image"
+	// ContainerNameAnnotation overrides the name of the bootstrap initContainer
+	ContainerNameAnnotation = "k8tz.io/container-name"
+	// BootstrapImageAnnotation overrides the image of the bootstrap
+	// initContainer
+	BootstrapImageAnnotation = "k8tz.io/bootstrap-image"
+	// BootstrapResourcesAnnotation overrides the compute resources of the
+	// bootstrap initContainer, in JSON format
+	BootstrapResourcesAnnotation = "k8tz.io/bootstrap-resources"
+	// BootstrapVerboseAnnotation enables verbose logs of the bootstrap
+	// initContainer
+	BootstrapVerboseAnnotation = "k8tz.io/bootstrap-verbose"
+	// HostPathPrefixAnnotation overrides the location of zoneinfo on the
+	// host for the hostPath injection strategy
+	HostPathPrefixAnnotation = "k8tz.io/host-path-prefix"
+	// LocalTimePathAnnotation overrides the mount path of the TZif file
+	LocalTimePathAnnotation = "k8tz.io/localtime-path"
+	// TimezoneFilePathAnnotation overrides the mount path of the file that
+	// contains the timezone name, or an empty value to disable it
+	TimezoneFilePathAnnotation = "k8tz.io/timezone-file-path"
+	// VolumeNameAnnotation overrides the name of the injected volume
+	VolumeNameAnnotation = "k8tz.io/volume-name"
+	// MountConflictPolicyAnnotation overrides what to do with containers that
+	// already mount a path used by k8tz
+	MountConflictPolicyAnnotation = "k8tz.io/mount-conflict-policy"
+	// ImagePullPolicyAnnotation overrides the pull policy of the injected
+	// images
+	ImagePullPolicyAnnotation = "k8tz.io/image-pull-policy"
+	// ImagePullSecretsAnnotation overrides the comma separated image pull
+	// secrets added for the injected images
+	ImagePullSecretsAnnotation = "k8tz.io/image-pull-secrets"
+	// EmptyDirSizeLimitAnnotation sets the size limit of the emptyDir volume
+	// of the initContainer injection strategy
+	EmptyDirSizeLimitAnnotation = "k8tz.io/empty-dir-size-limit"
+	// EmptyDirMediumAnnotation sets the medium of the emptyDir volume of the
+	// initContainer injection strategy
+	EmptyDirMediumAnnotation = "k8tz.io/empty-dir-medium"
 )
 
 var VerboseLogger = log.New(io.Discard, "VERBOSE: ", log.Ldate|log.Ltime|log.Lshortfile)
@@ -0,0 +1,200 @@
+/*
+Copyright © 2026 Yonatan Kahana
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tzconfigmap
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	k8tz "github.com/k8tz/k8tz/pkg"
+	"github.com/k8tz/k8tz/pkg/inject"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// ManagedByLabel marks the ConfigMaps that are maintained by k8tz
+	ManagedByLabel = "app.kubernetes.io/managed-by"
+	// ManagedByValue is the value of ManagedByLabel on k8tz ConfigMaps
+	ManagedByValue = "k8tz"
+
+	DefaultZoneinfoPath = "/usr/share/zoneinfo"
+)
+
+var tzifMagic = []byte("TZif")
+
+// Controller maintains the per-timezone ConfigMaps used by the configMap
+// injection strategy. ConfigMaps are created on demand by Ensure, when a pod
+// that uses them is admitted, and are kept in sync with the tzdata found at
+// ZoneinfoPath by Start, e.g. after k8tz is upgraded with a newer tzdata
+type Controller struct {
+	ZoneinfoPath string
+	clientset    kubernetes.Interface
+}
+
+func NewController(clientset kubernetes.Interface) *Controller {
+	return &Controller{
+		ZoneinfoPath: DefaultZoneinfoPath,
+		clientset:    clientset,
+	}
+}
+
+// Ensure creates or updates the ConfigMap of the timezone in the namespace
+func (c *Controller) Ensure(ctx context.Context, namespace string, timezone string) error {
+	desired, err := c.desiredConfigMap(namespace, timezone)
+	if err != nil {
+		return err
+	}
+
+	current, err := c.clientset.CoreV1().ConfigMaps(namespace).Get(ctx, desired.Name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		k8tz.InfoLogger.Printf("creating configmap %s/%s for timezone %s", namespace, desired.Name, timezone)
+		_, err = c.clientset.CoreV1().ConfigMaps(namespace).Create(ctx, desired, metav1.CreateOptions{})
+		if k8serrors.IsAlreadyExists(err) {
+			return nil
+		}
+
+		return err
+	} else if err != nil {
+		return fmt.Errorf("failed to get configmap %s/%s: %w", namespace, desired.Name, err)
+	}
+
+	return c.update(ctx, current, desired)
+}
+
+// Start watches the ConfigMaps managed by k8tz and updates any of them whose
+// content differs from the local tzdata, until ctx is done
+func (c *Controller) Start(ctx context.Context) error {
+	factory := informers.NewSharedInformerFactoryWithOptions(
+		c.clientset, 0,
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.LabelSelector = fmt.Sprintf("%s=%s", ManagedByLabel, ManagedByValue)
+		}),
+	)
+	configMapInformer := factory.Core().V1().ConfigMaps().Informer()
+
+	defer runtime.HandleCrash()
+
+	go factory.Start(ctx.Done())
+
+	if !cache.WaitForCacheSync(ctx.Done(), configMapInformer.HasSynced) {
+		return fmt.Errorf("timed out waiting for configMapInformer caches to sync")
+	}
+
+	_, err := configMapInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.reconcile(ctx, obj.(*corev1.ConfigMap))
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			c.reconcile(ctx, newObj.(*corev1.ConfigMap))
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to register EventHandler for configMapInformer")
+	}
+
+	<-ctx.Done()
+
+	return nil
+}
+
+func (c *Controller) reconcile(ctx context.Context, current *corev1.ConfigMap) {
+	timezone, ok := current.Annotations[k8tz.TimezoneAnnotation]
+	if !ok {
+		k8tz.WarningLogger.Printf("configmap %s/%s has no %s annotation, skipping", current.Namespace, current.Name, k8tz.TimezoneAnnotation)
+		return
+	}
+
+	desired, err := c.desiredConfigMap(current.Namespace, timezone)
+	if err != nil {
+		k8tz.ErrorLogger.Printf("failed to build configmap %s/%s: %v", current.Namespace, current.Name, err)
+		return
+	}
+
+	if err := c.update(ctx, current, desired); err != nil {
+		k8tz.ErrorLogger.Printf("failed to update configmap %s/%s: %v", current.Namespace, current.Name, err)
+	}
+}
+
+func (c *Controller) update(ctx context.Context, current *corev1.ConfigMap, desired *corev1.ConfigMap) error {
+	if reflect.DeepEqual(current.Data, desired.Data) && reflect.DeepEqual(current.BinaryData, desired.BinaryData) {
+		return nil
+	}
+
+	k8tz.InfoLogger.Printf("updating configmap %s/%s", current.Namespace, current.Name)
+	updated := current.DeepCopy()
+	updated.Data = desired.Data
+	updated.BinaryData = desired.BinaryData
+	_, err := c.clientset.CoreV1().ConfigMaps(current.Namespace).Update(ctx, updated, metav1.UpdateOptions{})
+	return err
+}
+
+func (c *Controller) desiredConfigMap(namespace string, timezone string) (*corev1.ConfigMap, error) {
+	tzif, err := c.readTZif(timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      inject.ConfigMapName(timezone),
+			Namespace: namespace,
+			Labels: map[string]string{
+				ManagedByLabel: ManagedByValue,
+			},
+			Annotations: map[string]string{
+				k8tz.TimezoneAnnotation: timezone,
+			},
+		},
+		Data: map[string]string{
+			inject.ConfigMapTimezoneKey: timezone + "\n",
+		},
+		BinaryData: map[string][]byte{
+			inject.ConfigMapLocalTimeKey: tzif,
+		},
+	}, nil
+}
+
+// readTZif reads the TZif file of the timezone, refusing names that would
+// resolve outside of ZoneinfoPath
+func (c *Controller) readTZif(timezone string) ([]byte, error) {
+	if timezone == "" || filepath.IsAbs(timezone) || strings.Contains(timezone, "..") {
+		return nil, fmt.Errorf("invalid timezone: %s", timezone)
+	}
+
+	tzif, err := os.ReadFile(filepath.Join(c.ZoneinfoPath, filepath.FromSlash(timezone)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read timezone %s: %w", timezone, err)
+	}
+
+	if !bytes.HasPrefix(tzif, tzifMagic) {
+		return nil, fmt.Errorf("timezone %s is not a TZif file", timezone)
+	}
+
+	return tzif, nil
+}
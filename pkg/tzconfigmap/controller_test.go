@@ -0,0 +1,140 @@
+/*
+Copyright © 2026 Yonatan Kahana
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tzconfigmap
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	k8tz "github.com/k8tz/k8tz/pkg"
+	"github.com/k8tz/k8tz/pkg/inject"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func testZoneinfo(t *testing.T, files map[string]string) string {
+	root := t.TempDir()
+	for name, content := range files {
+		file := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return root
+}
+
+func TestController_Ensure(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	c := NewController(clientset)
+	c.ZoneinfoPath = testZoneinfo(t, map[string]string{"Europe/London": "TZif-london-v1"})
+
+	if err := c.Ensure(context.TODO(), "default", "Europe/London"); err != nil {
+		t.Fatalf("Controller.Ensure() error = %v", err)
+	}
+
+	name := inject.ConfigMapName("Europe/London")
+	configMap, err := clientset.CoreV1().ConfigMaps("default").Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("configmap %s was not created: %v", name, err)
+	}
+	if got := string(configMap.BinaryData[inject.ConfigMapLocalTimeKey]); got != "TZif-london-v1" {
+		t.Errorf("configmap localtime = %q, want %q", got, "TZif-london-v1")
+	}
+	if got := configMap.Data[inject.ConfigMapTimezoneKey]; got != "Europe/London\n" {
+		t.Errorf("configmap timezone = %q, want %q", got, "Europe/London\n")
+	}
+	if got := configMap.Labels[ManagedByLabel]; got != ManagedByValue {
+		t.Errorf("configmap %s label = %q, want %q", ManagedByLabel, got, ManagedByValue)
+	}
+
+	if err := os.WriteFile(filepath.Join(c.ZoneinfoPath, "Europe", "London"), []byte("TZif-london-v2"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Ensure(context.TODO(), "default", "Europe/London"); err != nil {
+		t.Fatalf("Controller.Ensure() error = %v", err)
+	}
+
+	configMap, err = clientset.CoreV1().ConfigMaps("default").Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(configMap.BinaryData[inject.ConfigMapLocalTimeKey]); got != "TZif-london-v2" {
+		t.Errorf("configmap localtime after update = %q, want %q", got, "TZif-london-v2")
+	}
+}
+
+func TestController_reconcile(t *testing.T) {
+	stale := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        inject.ConfigMapName("Asia/Tokyo"),
+			Namespace:   "apps",
+			Labels:      map[string]string{ManagedByLabel: ManagedByValue},
+			Annotations: map[string]string{k8tz.TimezoneAnnotation: "Asia/Tokyo"},
+		},
+		BinaryData: map[string][]byte{inject.ConfigMapLocalTimeKey: []byte("TZif-old")},
+	}
+	clientset := fake.NewSimpleClientset(stale)
+	c := NewController(clientset)
+	c.ZoneinfoPath = testZoneinfo(t, map[string]string{"Asia/Tokyo": "TZif-new"})
+
+	c.reconcile(context.TODO(), stale)
+
+	configMap, err := clientset.CoreV1().ConfigMaps("apps").Get(context.TODO(), stale.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(configMap.BinaryData[inject.ConfigMapLocalTimeKey]); got != "TZif-new" {
+		t.Errorf("configmap localtime = %q, want %q", got, "TZif-new")
+	}
+}
+
+func TestController_readTZif(t *testing.T) {
+	c := NewController(nil)
+	c.ZoneinfoPath = testZoneinfo(t, map[string]string{
+		"UTC":      "TZif-utc",
+		"zone.tab": "# not a TZif file",
+	})
+
+	tests := []struct {
+		name     string
+		timezone string
+		wantErr  bool
+	}{
+		{name: "valid timezone", timezone: "UTC", wantErr: false},
+		{name: "missing timezone", timezone: "Mars/Olympus_Mons", wantErr: true},
+		{name: "not a TZif file", timezone: "zone.tab", wantErr: true},
+		{name: "path traversal", timezone: "../" + filepath.Base(c.ZoneinfoPath) + "/UTC", wantErr: true},
+		{name: "absolute path", timezone: filepath.Join(c.ZoneinfoPath, "UTC"), wantErr: true},
+		{name: "empty timezone", timezone: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := c.readTZif(tt.timezone); (err != nil) != tt.wantErr {
+				t.Errorf("Controller.readTZif() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}